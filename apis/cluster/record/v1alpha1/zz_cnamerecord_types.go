@@ -17,8 +17,18 @@ type CNAMERecordInitParameters struct {
 
 	// (String) The canonical name this record will point to.
 	// The canonical name this record will point to.
+	// +crossplane:generate:reference:type=github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1.ARecordSet
+	// +crossplane:generate:reference:extractor=github.com/crossplane/upjet/v2/pkg/resource.ExtractParamPath("id",true)
 	Cname *string `json:"cname,omitempty" tf:"cname,omitempty"`
 
+	// Reference to a ARecordSet in recordset to populate cname.
+	// +kubebuilder:validation:Optional
+	CnameRef *v1.Reference `json:"cnameRef,omitempty" tf:"-"`
+
+	// Selector for a ARecordSet in recordset to populate cname.
+	// +kubebuilder:validation:Optional
+	CnameSelector *v1.Selector `json:"cnameSelector,omitempty" tf:"-"`
+
 	// (String) The name of the record. The zone argument will be appended to this value to create the full record path.
 	// The name of the record. The `zone` argument will be appended to this value to create the full record path.
 	Name *string `json:"name,omitempty" tf:"name,omitempty"`
@@ -54,9 +64,19 @@ type CNAMERecordParameters struct {
 
 	// (String) The canonical name this record will point to.
 	// The canonical name this record will point to.
+	// +crossplane:generate:reference:type=github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1.ARecordSet
+	// +crossplane:generate:reference:extractor=github.com/crossplane/upjet/v2/pkg/resource.ExtractParamPath("id",true)
 	// +kubebuilder:validation:Optional
 	Cname *string `json:"cname,omitempty" tf:"cname,omitempty"`
 
+	// Reference to a ARecordSet in recordset to populate cname.
+	// +kubebuilder:validation:Optional
+	CnameRef *v1.Reference `json:"cnameRef,omitempty" tf:"-"`
+
+	// Selector for a ARecordSet in recordset to populate cname.
+	// +kubebuilder:validation:Optional
+	CnameSelector *v1.Selector `json:"cnameSelector,omitempty" tf:"-"`
+
 	// (String) The name of the record. The zone argument will be appended to this value to create the full record path.
 	// The name of the record. The `zone` argument will be appended to this value to create the full record path.
 	// +kubebuilder:validation:Optional
@@ -109,7 +129,6 @@ type CNAMERecordStatus struct {
 type CNAMERecord struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.cname) || (has(self.initProvider) && has(self.initProvider.cname))",message="spec.forProvider.cname is a required parameter"
 	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.name) || (has(self.initProvider) && has(self.initProvider.name))",message="spec.forProvider.name is a required parameter"
 	Spec   CNAMERecordSpec   `json:"spec"`
 	Status CNAMERecordStatus `json:"status,omitempty"`
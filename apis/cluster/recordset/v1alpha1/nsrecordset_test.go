@@ -0,0 +1,110 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func to[T any](v T) *T { return &v }
+
+// aRecordSet returns an A record set with the given labels, observed with the
+// given id, which is its FQDN.
+func aRecordSet(name, id string, labels map[string]string) *ARecordSet {
+	rs := &ARecordSet{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	if id != "" {
+		rs.Status.AtProvider.ID = to(id)
+	}
+	return rs
+}
+
+func TestNSRecordSetResolveReferences(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	objs := []client.Object{
+		aRecordSet("ns1", "ns1.example.com.", map[string]string{"delegation": "sub"}),
+		aRecordSet("ns2", "ns2.example.com.", map[string]string{"delegation": "sub"}),
+		aRecordSet("ns3", "ns3.example.com.", nil),
+		aRecordSet("pending", "", nil),
+	}
+
+	type want struct {
+		nameservers []*string
+		refs        []xpv1.Reference
+		err         error
+	}
+
+	cases := map[string]struct {
+		reason string
+		params NSRecordSetParameters
+		want   want
+	}{
+		"Values": {
+			reason: "Nameservers set by value should be kept.",
+			params: NSRecordSetParameters{Nameservers: []*string{to("ns9.example.com.")}},
+			want:   want{nameservers: []*string{to("ns9.example.com.")}},
+		},
+		"MultipleReferences": {
+			reason: "Every reference should be resolved to the FQDN of the referenced record set. Nameservers are a set, so they are sorted along with their references.",
+			params: NSRecordSetParameters{NameserversRefs: []xpv1.Reference{{Name: "ns3"}, {Name: "ns1"}}},
+			want: want{
+				nameservers: []*string{to("ns1.example.com."), to("ns3.example.com.")},
+				refs:        []xpv1.Reference{{Name: "ns1"}, {Name: "ns3"}},
+			},
+		},
+		"Selector": {
+			reason: "A selector should resolve every matching record set.",
+			params: NSRecordSetParameters{NameserversSelector: &xpv1.Selector{MatchLabels: map[string]string{"delegation": "sub"}}},
+			want: want{
+				nameservers: []*string{to("ns1.example.com."), to("ns2.example.com.")},
+				refs:        []xpv1.Reference{{Name: "ns1"}, {Name: "ns2"}},
+			},
+		},
+		"PartialFailureNotFound": {
+			reason: "A reference to a missing record set should fail the resolution as a whole, leaving the nameservers unresolved.",
+			params: NSRecordSetParameters{NameserversRefs: []xpv1.Reference{{Name: "ns1"}, {Name: "missing"}}},
+			want: want{
+				refs: []xpv1.Reference{{Name: "ns1"}, {Name: "missing"}},
+				err:  errors.Wrap(errors.Wrap(kerrors.NewNotFound(CRDGroupVersion.WithResource("arecordsets").GroupResource(), "missing"), "cannot get referenced resource"), "mg.Spec.ForProvider.Nameservers"),
+			},
+		},
+		"PartialFailureNotReady": {
+			reason: "A reference to a record set that is not yet observed should fail the resolution as a whole, leaving the nameservers unresolved.",
+			params: NSRecordSetParameters{NameserversRefs: []xpv1.Reference{{Name: "ns1"}, {Name: "pending"}}},
+			want: want{
+				refs: []xpv1.Reference{{Name: "ns1"}, {Name: "pending"}},
+				err:  errors.Wrap(errors.New("referenced field was empty (referenced resource may not yet be ready)"), "mg.Spec.ForProvider.Nameservers"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &NSRecordSet{ObjectMeta: metav1.ObjectMeta{Name: "sub"}}
+			mg.Spec.ForProvider = tc.params
+			c := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+
+			err := mg.ResolveReferences(context.Background(), c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveReferences(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.nameservers, mg.Spec.ForProvider.Nameservers); diff != "" {
+				t.Errorf("\n%s\nResolveReferences(...): -want nameservers, +got nameservers:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.refs, mg.Spec.ForProvider.NameserversRefs); diff != "" {
+				t.Errorf("\n%s\nResolveReferences(...): -want references, +got references:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@
 package v1alpha1
 
 import (
+	"github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -659,6 +660,16 @@ func (in *MxInitParameters) DeepCopyInto(out *MxInitParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ExchangeRef != nil {
+		in, out := &in.ExchangeRef, &out.ExchangeRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExchangeSelector != nil {
+		in, out := &in.ExchangeSelector, &out.ExchangeSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Preference != nil {
 		in, out := &in.Preference, &out.Preference
 		*out = new(float64)
@@ -709,6 +720,16 @@ func (in *MxParameters) DeepCopyInto(out *MxParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ExchangeRef != nil {
+		in, out := &in.ExchangeRef, &out.ExchangeRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExchangeSelector != nil {
+		in, out := &in.ExchangeSelector, &out.ExchangeSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Preference != nil {
 		in, out := &in.Preference, &out.Preference
 		*out = new(float64)
@@ -772,6 +793,18 @@ func (in *NSRecordSetInitParameters) DeepCopyInto(out *NSRecordSetInitParameters
 			}
 		}
 	}
+	if in.NameserversRefs != nil {
+		in, out := &in.NameserversRefs, &out.NameserversRefs
+		*out = make([]v1.Reference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NameserversSelector != nil {
+		in, out := &in.NameserversSelector, &out.NameserversSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TTL != nil {
 		in, out := &in.TTL, &out.TTL
 		*out = new(float64)
@@ -886,6 +919,18 @@ func (in *NSRecordSetParameters) DeepCopyInto(out *NSRecordSetParameters) {
 			}
 		}
 	}
+	if in.NameserversRefs != nil {
+		in, out := &in.NameserversRefs, &out.NameserversRefs
+		*out = make([]v1.Reference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NameserversSelector != nil {
+		in, out := &in.NameserversSelector, &out.NameserversSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TTL != nil {
 		in, out := &in.TTL, &out.TTL
 		*out = new(float64)
@@ -1166,6 +1211,16 @@ func (in *SrvInitParameters) DeepCopyInto(out *SrvInitParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetSelector != nil {
+		in, out := &in.TargetSelector, &out.TargetSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Weight != nil {
 		in, out := &in.Weight, &out.Weight
 		*out = new(float64)
@@ -1236,6 +1291,16 @@ func (in *SrvParameters) DeepCopyInto(out *SrvParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(v1.Reference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetSelector != nil {
+		in, out := &in.TargetSelector, &out.TargetSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Weight != nil {
 		in, out := &in.Weight, &out.Weight
 		*out = new(float64)
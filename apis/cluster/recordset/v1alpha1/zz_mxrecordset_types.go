@@ -77,8 +77,18 @@ type MxInitParameters struct {
 
 	// (String) The FQDN of the mail exchange, include the trailing dot.
 	// The FQDN of the mail exchange, include the trailing dot.
+	// +crossplane:generate:reference:type=github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1.ARecordSet
+	// +crossplane:generate:reference:extractor=github.com/crossplane/upjet/v2/pkg/resource.ExtractParamPath("id",true)
 	Exchange *string `json:"exchange,omitempty" tf:"exchange,omitempty"`
 
+	// Reference to a ARecordSet in recordset to populate exchange.
+	// +kubebuilder:validation:Optional
+	ExchangeRef *v1.Reference `json:"exchangeRef,omitempty" tf:"-"`
+
+	// Selector for a ARecordSet in recordset to populate exchange.
+	// +kubebuilder:validation:Optional
+	ExchangeSelector *v1.Selector `json:"exchangeSelector,omitempty" tf:"-"`
+
 	// (Number) The preference for the record.
 	// The preference for the record.
 	Preference *float64 `json:"preference,omitempty" tf:"preference,omitempty"`
@@ -99,8 +109,18 @@ type MxParameters struct {
 
 	// (String) The FQDN of the mail exchange, include the trailing dot.
 	// The FQDN of the mail exchange, include the trailing dot.
+	// +crossplane:generate:reference:type=github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1.ARecordSet
+	// +crossplane:generate:reference:extractor=github.com/crossplane/upjet/v2/pkg/resource.ExtractParamPath("id",true)
+	// +kubebuilder:validation:Optional
+	Exchange *string `json:"exchange,omitempty" tf:"exchange,omitempty"`
+
+	// Reference to a ARecordSet in recordset to populate exchange.
+	// +kubebuilder:validation:Optional
+	ExchangeRef *v1.Reference `json:"exchangeRef,omitempty" tf:"-"`
+
+	// Selector for a ARecordSet in recordset to populate exchange.
 	// +kubebuilder:validation:Optional
-	Exchange *string `json:"exchange" tf:"exchange,omitempty"`
+	ExchangeSelector *v1.Selector `json:"exchangeSelector,omitempty" tf:"-"`
 
 	// (Number) The preference for the record.
 	// The preference for the record.
@@ -21,9 +21,19 @@ type NSRecordSetInitParameters struct {
 
 	// (Set of String) The nameservers this record set will point to.
 	// The nameservers this record set will point to.
+	// +crossplane:generate:reference:type=github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1.ARecordSet
+	// +crossplane:generate:reference:extractor=github.com/crossplane/upjet/v2/pkg/resource.ExtractParamPath("id",true)
 	// +listType=set
 	Nameservers []*string `json:"nameservers,omitempty" tf:"nameservers,omitempty"`
 
+	// References to ARecordSet in recordset to populate nameservers.
+	// +kubebuilder:validation:Optional
+	NameserversRefs []v1.Reference `json:"nameserversRefs,omitempty" tf:"-"`
+
+	// Selector for a list of ARecordSet in recordset to populate nameservers.
+	// +kubebuilder:validation:Optional
+	NameserversSelector *v1.Selector `json:"nameserversSelector,omitempty" tf:"-"`
+
 	// (Number) The TTL of the record set. Defaults to 3600.
 	// The TTL of the record set. Defaults to `3600`.
 	TTL *float64 `json:"ttl,omitempty" tf:"ttl,omitempty"`
@@ -61,10 +71,20 @@ type NSRecordSetParameters struct {
 
 	// (Set of String) The nameservers this record set will point to.
 	// The nameservers this record set will point to.
+	// +crossplane:generate:reference:type=github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1.ARecordSet
+	// +crossplane:generate:reference:extractor=github.com/crossplane/upjet/v2/pkg/resource.ExtractParamPath("id",true)
 	// +kubebuilder:validation:Optional
 	// +listType=set
 	Nameservers []*string `json:"nameservers,omitempty" tf:"nameservers,omitempty"`
 
+	// References to ARecordSet in recordset to populate nameservers.
+	// +kubebuilder:validation:Optional
+	NameserversRefs []v1.Reference `json:"nameserversRefs,omitempty" tf:"-"`
+
+	// Selector for a list of ARecordSet in recordset to populate nameservers.
+	// +kubebuilder:validation:Optional
+	NameserversSelector *v1.Selector `json:"nameserversSelector,omitempty" tf:"-"`
+
 	// (Number) The TTL of the record set. Defaults to 3600.
 	// The TTL of the record set. Defaults to `3600`.
 	// +kubebuilder:validation:Optional
@@ -113,7 +133,6 @@ type NSRecordSet struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.name) || (has(self.initProvider) && has(self.initProvider.name))",message="spec.forProvider.name is a required parameter"
-	// +kubebuilder:validation:XValidation:rule="!('*' in self.managementPolicies || 'Create' in self.managementPolicies || 'Update' in self.managementPolicies) || has(self.forProvider.nameservers) || (has(self.initProvider) && has(self.initProvider.nameservers))",message="spec.forProvider.nameservers is a required parameter"
 	Spec   NSRecordSetSpec   `json:"spec"`
 	Status NSRecordSetStatus `json:"status,omitempty"`
 }
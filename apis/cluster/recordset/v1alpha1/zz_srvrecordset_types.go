@@ -85,8 +85,18 @@ type SrvInitParameters struct {
 
 	// (String) The FQDN of the target, include the trailing dot.
 	// The FQDN of the target, include the trailing dot.
+	// +crossplane:generate:reference:type=github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1.ARecordSet
+	// +crossplane:generate:reference:extractor=github.com/crossplane/upjet/v2/pkg/resource.ExtractParamPath("id",true)
 	Target *string `json:"target,omitempty" tf:"target,omitempty"`
 
+	// Reference to a ARecordSet in recordset to populate target.
+	// +kubebuilder:validation:Optional
+	TargetRef *v1.Reference `json:"targetRef,omitempty" tf:"-"`
+
+	// Selector for a ARecordSet in recordset to populate target.
+	// +kubebuilder:validation:Optional
+	TargetSelector *v1.Selector `json:"targetSelector,omitempty" tf:"-"`
+
 	// (Number) The weight for the record.
 	// The weight for the record.
 	Weight *float64 `json:"weight,omitempty" tf:"weight,omitempty"`
@@ -125,8 +135,18 @@ type SrvParameters struct {
 
 	// (String) The FQDN of the target, include the trailing dot.
 	// The FQDN of the target, include the trailing dot.
+	// +crossplane:generate:reference:type=github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1.ARecordSet
+	// +crossplane:generate:reference:extractor=github.com/crossplane/upjet/v2/pkg/resource.ExtractParamPath("id",true)
+	// +kubebuilder:validation:Optional
+	Target *string `json:"target,omitempty" tf:"target,omitempty"`
+
+	// Reference to a ARecordSet in recordset to populate target.
+	// +kubebuilder:validation:Optional
+	TargetRef *v1.Reference `json:"targetRef,omitempty" tf:"-"`
+
+	// Selector for a ARecordSet in recordset to populate target.
 	// +kubebuilder:validation:Optional
-	Target *string `json:"target" tf:"target,omitempty"`
+	TargetSelector *v1.Selector `json:"targetSelector,omitempty" tf:"-"`
 
 	// (Number) The weight for the record.
 	// The weight for the record.
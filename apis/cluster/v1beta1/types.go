@@ -8,10 +8,146 @@ import (
 
 // A ProviderConfigSpec defines the desired state of a ProviderConfig.
 type ProviderConfigSpec struct {
+	// Description is a human-readable description of this ProviderConfig,
+	// included in the logs of the resources using it.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// MaxRecordSetSize is the maximum number of values a single record set
+	// using this ProviderConfig may hold. Defaults to 1000.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxRecordSetSize *int `json:"maxRecordSetSize,omitempty"`
+
+	// TCPFallbackThreshold is the estimated update size in bytes above which
+	// updates are sent over TCP, regardless of the configured transport, to
+	// avoid UDP truncation.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TCPFallbackThreshold *int `json:"tcpFallbackThreshold,omitempty"`
+
+	// MaxRetries is the largest retries value used. Larger values of the
+	// credentials or profiles are lowered to it, to avoid retry storms
+	// against the DNS server. Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int `json:"maxRetries,omitempty"`
+
+	// ExplicitDefaults sets the port, retries, timeout and transport to their
+	// documented defaults when the credentials do not set them, instead of
+	// relying on the defaults of the Terraform provider.
+	// +optional
+	ExplicitDefaults bool `json:"explicitDefaults,omitempty"`
+
+	// Profiles override the retries, timeout and transport of the credentials
+	// for the records of the zones they match.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Profiles []ZoneProfile `json:"profiles,omitempty"`
+
+	// DefaultProfile is the name of the profile used for the records of zones
+	// not matched by any profile.
+	// +optional
+	DefaultProfile string `json:"defaultProfile,omitempty"`
+
+	// TSIGKeys are named TSIG keys, which records select with the
+	// dns-v2.crossplane.io/tsig-key annotation instead of the key of the
+	// credentials.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	TSIGKeys []TSIGKey `json:"tsigKeys,omitempty"`
+
+	// Connection holds connection settings that may be set here instead of
+	// in the credentials, keeping only secret material in the credentials.
+	// Settings also present in the credentials are overridden by them.
+	// +optional
+	Connection *ConnectionSettings `json:"connection,omitempty"`
+
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
 }
 
+// A TSIGKey is a TSIG key records may select instead of the key of the
+// credentials.
+type TSIGKey struct {
+	// Name records select the key by.
+	Name string `json:"name"`
+
+	// KeyName is the name of the key, sent as key_name.
+	KeyName string `json:"keyName"`
+
+	// KeyAlgorithm is the algorithm of the key. Defaults to the key_algorithm
+	// of the credentials.
+	// +optional
+	KeyAlgorithm string `json:"keyAlgorithm,omitempty"`
+
+	// SecretKey is the key of the credentials Secret holding the secret of the
+	// key.
+	SecretKey string `json:"secretKey"`
+}
+
+// ConnectionSettings are the non-secret settings of the connection to the DNS
+// server.
+type ConnectionSettings struct {
+	// Server is the DNS server updates are sent to.
+	// +optional
+	Server string `json:"server,omitempty"`
+
+	// Port on the server where updates are sent to.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port *int `json:"port,omitempty"`
+
+	// RFC selects the authentication model, 2845 for TSIG, 3645 for GSS-TSIG
+	// or none for unauthenticated updates.
+	// +optional
+	// +kubebuilder:validation:Enum="2845";"3645";none
+	RFC string `json:"rfc,omitempty"`
+
+	// Transport used to send updates.
+	// +optional
+	// +kubebuilder:validation:Enum=udp;udp4;udp6;tcp;tcp4;tcp6
+	Transport string `json:"transport,omitempty"`
+
+	// Retries is the number of times an update is retried.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Retries *int `json:"retries,omitempty"`
+
+	// Timeout of an update, a duration such as 30s or a number of seconds.
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// A ZoneProfile holds the connection settings used for the records of a set
+// of zones.
+type ZoneProfile struct {
+	// Name of the profile.
+	Name string `json:"name"`
+
+	// Zones are the zone suffixes this profile applies to. When several
+	// profiles match a zone, the one with the longest suffix is used.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+
+	// Retries overrides the retries of the credentials.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Retries *int `json:"retries,omitempty"`
+
+	// Timeout overrides the timeout of the credentials.
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+
+	// Transport overrides the transport of the credentials.
+	// +optional
+	// +kubebuilder:validation:Enum=udp;udp4;udp6;tcp;tcp4;tcp6
+	Transport string `json:"transport,omitempty"`
+}
+
 // ProviderCredentials required to authenticate.
 type ProviderCredentials struct {
 	// Source of the provider credentials.
@@ -19,6 +155,12 @@ type ProviderCredentials struct {
 	Source xpv1.CredentialsSource `json:"source"`
 
 	xpv1.CommonCredentialSelectors `json:",inline"`
+
+	// SecretClusterRef references a Secret key holding a kubeconfig. When set,
+	// the credentials Secret is read from the cluster of that kubeconfig
+	// instead of the cluster the provider runs in.
+	// +optional
+	SecretClusterRef *xpv1.SecretKeySelector `json:"secretClusterRef,omitempty"`
 }
 
 // A ProviderConfigStatus reflects the observed state of a ProviderConfig.
@@ -32,6 +174,7 @@ type ProviderConfigStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
+// +kubebuilder:printcolumn:name="DESCRIPTION",type="string",JSONPath=".spec.description",priority=1
 // +kubebuilder:resource:scope=Cluster
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,dns-v2}
 type ProviderConfig struct {
@@ -9,9 +9,35 @@
 package v1beta1
 
 import (
+	"github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionSettings) DeepCopyInto(out *ConnectionSettings) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int)
+		**out = **in
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionSettings.
+func (in *ConnectionSettings) DeepCopy() *ConnectionSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
 	*out = *in
@@ -74,6 +100,38 @@ func (in *ProviderConfigList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfigSpec) DeepCopyInto(out *ProviderConfigSpec) {
 	*out = *in
+	if in.MaxRecordSetSize != nil {
+		in, out := &in.MaxRecordSetSize, &out.MaxRecordSetSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.TCPFallbackThreshold != nil {
+		in, out := &in.TCPFallbackThreshold, &out.TCPFallbackThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int)
+		**out = **in
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]ZoneProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TSIGKeys != nil {
+		in, out := &in.TSIGKeys, &out.TSIGKeys
+		*out = make([]TSIGKey, len(*in))
+		copy(*out, *in)
+	}
+	if in.Connection != nil {
+		in, out := &in.Connection, &out.Connection
+		*out = new(ConnectionSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Credentials.DeepCopyInto(&out.Credentials)
 }
 
@@ -165,6 +223,11 @@ func (in *ProviderConfigUsageList) DeepCopyObject() runtime.Object {
 func (in *ProviderCredentials) DeepCopyInto(out *ProviderCredentials) {
 	*out = *in
 	in.CommonCredentialSelectors.DeepCopyInto(&out.CommonCredentialSelectors)
+	if in.SecretClusterRef != nil {
+		in, out := &in.SecretClusterRef, &out.SecretClusterRef
+		*out = new(v1.SecretKeySelector)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderCredentials.
@@ -176,3 +239,43 @@ func (in *ProviderCredentials) DeepCopy() *ProviderCredentials {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TSIGKey) DeepCopyInto(out *TSIGKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TSIGKey.
+func (in *TSIGKey) DeepCopy() *TSIGKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TSIGKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneProfile) DeepCopyInto(out *ZoneProfile) {
+	*out = *in
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneProfile.
+func (in *ZoneProfile) DeepCopy() *ZoneProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneProfile)
+	in.DeepCopyInto(out)
+	return out
+}
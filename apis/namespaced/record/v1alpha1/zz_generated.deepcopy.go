@@ -9,6 +9,7 @@
 package v1alpha1
 
 import (
+	"github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -47,6 +48,16 @@ func (in *CNAMERecordInitParameters) DeepCopyInto(out *CNAMERecordInitParameters
 		*out = new(string)
 		**out = **in
 	}
+	if in.CnameRef != nil {
+		in, out := &in.CnameRef, &out.CnameRef
+		*out = new(v1.NamespacedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CnameSelector != nil {
+		in, out := &in.CnameSelector, &out.CnameSelector
+		*out = new(v1.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Name != nil {
 		in, out := &in.Name, &out.Name
 		*out = new(string)
@@ -149,6 +160,16 @@ func (in *CNAMERecordParameters) DeepCopyInto(out *CNAMERecordParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.CnameRef != nil {
+		in, out := &in.CnameRef, &out.CnameRef
+		*out = new(v1.NamespacedReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CnameSelector != nil {
+		in, out := &in.CnameSelector, &out.CnameSelector
+		*out = new(v1.NamespacedSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Name != nil {
 		in, out := &in.Name, &out.Name
 		*out = new(string)
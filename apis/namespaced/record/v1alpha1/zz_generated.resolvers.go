@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by angryjet. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	reference "github.com/crossplane/crossplane-runtime/v2/pkg/reference"
+	resource "github.com/crossplane/upjet/v2/pkg/resource"
+	v1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/recordset/v1alpha1"
+	errors "github.com/pkg/errors"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveReferences of this CNAMERecord.
+func (mg *CNAMERecord) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	var rsp reference.NamespacedResolutionResponse
+	var err error
+
+	rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.Cname),
+		Extract:      resource.ExtractParamPath("id", true),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.ForProvider.CnameRef,
+		Selector:     mg.Spec.ForProvider.CnameSelector,
+		To: reference.To{
+			List:    &v1alpha1.ARecordSetList{},
+			Managed: &v1alpha1.ARecordSet{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.Cname")
+	}
+	mg.Spec.ForProvider.Cname = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.CnameRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.InitProvider.Cname),
+		Extract:      resource.ExtractParamPath("id", true),
+		Namespace:    mg.GetNamespace(),
+		Reference:    mg.Spec.InitProvider.CnameRef,
+		Selector:     mg.Spec.InitProvider.CnameSelector,
+		To: reference.To{
+			List:    &v1alpha1.ARecordSetList{},
+			Managed: &v1alpha1.ARecordSet{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.InitProvider.Cname")
+	}
+	mg.Spec.InitProvider.Cname = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.InitProvider.CnameRef = rsp.ResolvedReference
+
+	return nil
+}
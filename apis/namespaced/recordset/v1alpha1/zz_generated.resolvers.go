@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2024 The Crossplane Authors <https://crossplane.io>
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by angryjet. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	reference "github.com/crossplane/crossplane-runtime/v2/pkg/reference"
+	resource "github.com/crossplane/upjet/v2/pkg/resource"
+	errors "github.com/pkg/errors"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolveReferences of this MXRecordSet.
+func (mg *MXRecordSet) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	var rsp reference.NamespacedResolutionResponse
+	var err error
+
+	for i3 := 0; i3 < len(mg.Spec.ForProvider.Mx); i3++ {
+		rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+			CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.Mx[i3].Exchange),
+			Extract:      resource.ExtractParamPath("id", true),
+			Namespace:    mg.GetNamespace(),
+			Reference:    mg.Spec.ForProvider.Mx[i3].ExchangeRef,
+			Selector:     mg.Spec.ForProvider.Mx[i3].ExchangeSelector,
+			To: reference.To{
+				List:    &ARecordSetList{},
+				Managed: &ARecordSet{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Mx[i3].Exchange")
+		}
+		mg.Spec.ForProvider.Mx[i3].Exchange = reference.ToPtrValue(rsp.ResolvedValue)
+		mg.Spec.ForProvider.Mx[i3].ExchangeRef = rsp.ResolvedReference
+
+	}
+	for i3 := 0; i3 < len(mg.Spec.InitProvider.Mx); i3++ {
+		rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+			CurrentValue: reference.FromPtrValue(mg.Spec.InitProvider.Mx[i3].Exchange),
+			Extract:      resource.ExtractParamPath("id", true),
+			Namespace:    mg.GetNamespace(),
+			Reference:    mg.Spec.InitProvider.Mx[i3].ExchangeRef,
+			Selector:     mg.Spec.InitProvider.Mx[i3].ExchangeSelector,
+			To: reference.To{
+				List:    &ARecordSetList{},
+				Managed: &ARecordSet{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.InitProvider.Mx[i3].Exchange")
+		}
+		mg.Spec.InitProvider.Mx[i3].Exchange = reference.ToPtrValue(rsp.ResolvedValue)
+		mg.Spec.InitProvider.Mx[i3].ExchangeRef = rsp.ResolvedReference
+
+	}
+
+	return nil
+}
+
+// ResolveReferences of this NSRecordSet.
+func (mg *NSRecordSet) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	var mrsp reference.MultiNamespacedResolutionResponse
+	var err error
+
+	mrsp, err = r.ResolveMultiple(ctx, reference.MultiNamespacedResolutionRequest{
+		CurrentValues: reference.FromPtrValues(mg.Spec.ForProvider.Nameservers),
+		Extract:       resource.ExtractParamPath("id", true),
+		Namespace:     mg.GetNamespace(),
+		References:    mg.Spec.ForProvider.NameserversRefs,
+		Selector:      mg.Spec.ForProvider.NameserversSelector,
+		To: reference.To{
+			List:    &ARecordSetList{},
+			Managed: &ARecordSet{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.ForProvider.Nameservers")
+	}
+	mg.Spec.ForProvider.Nameservers = reference.ToPtrValues(mrsp.ResolvedValues)
+	mg.Spec.ForProvider.NameserversRefs = mrsp.ResolvedReferences
+
+	mrsp, err = r.ResolveMultiple(ctx, reference.MultiNamespacedResolutionRequest{
+		CurrentValues: reference.FromPtrValues(mg.Spec.InitProvider.Nameservers),
+		Extract:       resource.ExtractParamPath("id", true),
+		Namespace:     mg.GetNamespace(),
+		References:    mg.Spec.InitProvider.NameserversRefs,
+		Selector:      mg.Spec.InitProvider.NameserversSelector,
+		To: reference.To{
+			List:    &ARecordSetList{},
+			Managed: &ARecordSet{},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "mg.Spec.InitProvider.Nameservers")
+	}
+	mg.Spec.InitProvider.Nameservers = reference.ToPtrValues(mrsp.ResolvedValues)
+	mg.Spec.InitProvider.NameserversRefs = mrsp.ResolvedReferences
+
+	return nil
+}
+
+// ResolveReferences of this SRVRecordSet.
+func (mg *SRVRecordSet) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPINamespacedResolver(c, mg)
+
+	var rsp reference.NamespacedResolutionResponse
+	var err error
+
+	for i3 := 0; i3 < len(mg.Spec.ForProvider.Srv); i3++ {
+		rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+			CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.Srv[i3].Target),
+			Extract:      resource.ExtractParamPath("id", true),
+			Namespace:    mg.GetNamespace(),
+			Reference:    mg.Spec.ForProvider.Srv[i3].TargetRef,
+			Selector:     mg.Spec.ForProvider.Srv[i3].TargetSelector,
+			To: reference.To{
+				List:    &ARecordSetList{},
+				Managed: &ARecordSet{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.ForProvider.Srv[i3].Target")
+		}
+		mg.Spec.ForProvider.Srv[i3].Target = reference.ToPtrValue(rsp.ResolvedValue)
+		mg.Spec.ForProvider.Srv[i3].TargetRef = rsp.ResolvedReference
+
+	}
+	for i3 := 0; i3 < len(mg.Spec.InitProvider.Srv); i3++ {
+		rsp, err = r.Resolve(ctx, reference.NamespacedResolutionRequest{
+			CurrentValue: reference.FromPtrValue(mg.Spec.InitProvider.Srv[i3].Target),
+			Extract:      resource.ExtractParamPath("id", true),
+			Namespace:    mg.GetNamespace(),
+			Reference:    mg.Spec.InitProvider.Srv[i3].TargetRef,
+			Selector:     mg.Spec.InitProvider.Srv[i3].TargetSelector,
+			To: reference.To{
+				List:    &ARecordSetList{},
+				Managed: &ARecordSet{},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "mg.Spec.InitProvider.Srv[i3].Target")
+		}
+		mg.Spec.InitProvider.Srv[i3].Target = reference.ToPtrValue(rsp.ResolvedValue)
+		mg.Spec.InitProvider.Srv[i3].TargetRef = rsp.ResolvedReference
+
+	}
+
+	return nil
+}
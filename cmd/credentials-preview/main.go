@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/alecthomas/kingpin/v2"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+	"github.com/dana-team/provider-dns-v2/internal/clients"
+)
+
+func main() {
+	var (
+		app              = kingpin.New(filepath.Base(os.Args[0]), "Validate Dns-v2 credentials and preview the provider configuration they produce, with secrets redacted.").DefaultEnvars()
+		file             = app.Arg("file", "File holding the credentials JSON. Reads standard input when omitted or -.").Default("-").String()
+		explicitDefaults = app.Flag("explicit-defaults", "Preview with the explicitDefaults of the ProviderConfig set.").Bool()
+		fileRoot         = app.Flag("credentials-file-root", "Directory the file: references of the credentials are resolved in. File references are rejected when unset.").String()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+	clients.SetCredentialsFileRoot(*fileRoot)
+
+	var data []byte
+	var err error
+	if *file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(*file)
+	}
+	kingpin.FatalIfError(err, "Cannot read the credentials")
+
+	config, unknown, err := clients.PreviewProviderConfig(data, &namespacedv1beta1.ProviderConfigSpec{ExplicitDefaults: *explicitDefaults})
+	for _, key := range unknown {
+		fmt.Fprintf(os.Stderr, "warning: ignoring unknown credentials key %q\n", key)
+	}
+	kingpin.FatalIfError(err, "Invalid credentials")
+
+	fmt.Print(config)
+}
@@ -38,8 +38,7 @@ import (
 	apisNamespaced "github.com/dana-team/provider-dns-v2/apis/namespaced"
 	"github.com/dana-team/provider-dns-v2/config"
 	"github.com/dana-team/provider-dns-v2/internal/clients"
-	controllerCluster "github.com/dana-team/provider-dns-v2/internal/controller/cluster"
-	controllerNamespaced "github.com/dana-team/provider-dns-v2/internal/controller/namespaced"
+	"github.com/dana-team/provider-dns-v2/internal/controller"
 	"github.com/dana-team/provider-dns-v2/internal/features"
 	"github.com/dana-team/provider-dns-v2/internal/version"
 )
@@ -71,6 +70,12 @@ func main() {
 
 		enableManagementPolicies = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("true").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
 		enableChangeLogs         = app.Flag("enable-changelogs", "Enable support for capturing change logs during reconciliation.").Default("false").Envar("ENABLE_CHANGE_LOGS").Bool()
+		controllerScope          = app.Flag("controller-scope", "Which controllers to run, either all, cluster or namespaced.").Default(string(controller.ScopeAll)).Envar("CONTROLLER_SCOPE").Enum(string(controller.ScopeAll), string(controller.ScopeCluster), string(controller.ScopeNamespaced))
+		usageDebounce            = app.Flag("usage-tracking-debounce", "Track the ProviderConfig usage of a resource at most once within this period, such as 30s. Tracks on every reconcile when zero.").Default("0s").Envar("USAGE_TRACKING_DEBOUNCE").Duration()
+		exportProviderConfig     = app.Flag("export-provider-config", "Log the effective Terraform provider configuration of each resource, with secrets redacted, to reproduce failures with plain Terraform.").Default("false").Envar("EXPORT_PROVIDER_CONFIG").Bool()
+		defaultTTL               = app.Flag("default-ttl", "TTL in seconds of the records and record sets that do not set one. The Terraform provider default of 3600 is used when zero.").Default("0").Envar("DEFAULT_TTL").Int()
+		trailingDotPolicy        = app.Flag("trailing-dot-policy", "How trailing dots of domain names in records are handled, either preserve or fqdn.").Default(string(config.TrailingDotPreserve)).Envar("TRAILING_DOT_POLICY").Enum(string(config.TrailingDotPreserve), string(config.TrailingDotFQDN))
+		credentialsFileRoot      = app.Flag("credentials-file-root", "Directory the file: references of the credentials are resolved in, such as a mounted volume. File references are rejected when unset.").Default("").Envar("CREDENTIALS_FILE_ROOT").String()
 
 		certsDirSet = false
 		// we record whether the command-line option "--certs-dir" was supplied
@@ -149,6 +154,16 @@ func main() {
 	metrics.Registry.MustRegister(metricRecorder)
 	metrics.Registry.MustRegister(stateMetrics)
 
+	clients.SetCredentialsFileRoot(*credentialsFileRoot)
+
+	setupMetrics := clients.NewSetupMetrics()
+	metrics.Registry.MustRegister(setupMetrics)
+
+	setupOpts := []clients.SetupOption{clients.WithUsageDebounce(*usageDebounce), clients.WithLogger(zl.WithName("provider-dns-v2").WithName("setup")), clients.WithMetrics(setupMetrics), clients.WithTrailingDotPolicy(config.TrailingDotPolicy(*trailingDotPolicy))}
+	if *exportProviderConfig {
+		setupOpts = append(setupOpts, clients.WithConfigExport())
+	}
+
 	clusterOpts := tjcontroller.Options{
 		Options: xpcontroller.Options{
 			Logger:                  log,
@@ -162,11 +177,11 @@ func main() {
 				MRStateMetrics:          stateMetrics,
 			},
 		},
-		Provider: config.GetProvider(ctx),
+		Provider: config.GetProvider(ctx, config.WithTrailingDotPolicy(config.TrailingDotPolicy(*trailingDotPolicy)), config.WithDefaultTTL(*defaultTTL)),
 		// use the following WorkspaceStoreOption to enable the shared gRPC mode
 		// terraform.WithProviderRunner(terraform.NewSharedProvider(log, os.Getenv("TERRAFORM_NATIVE_PROVIDER_PATH"), terraform.WithNativeProviderArgs("-debuggable")))
 		WorkspaceStore:        terraform.NewWorkspaceStore(log),
-		SetupFn:               clients.TerraformSetupBuilder(*terraformVersion, *providerSource, *providerVersion),
+		SetupFn:               clients.TerraformSetupBuilder(*terraformVersion, *providerSource, *providerVersion, setupOpts...),
 		StartWebhooks:         *certsDir != "",
 		OperationTrackerStore: tjcontroller.NewOperationStore(log),
 	}
@@ -184,11 +199,11 @@ func main() {
 				MRStateMetrics:          stateMetrics,
 			},
 		},
-		Provider: config.GetProviderNamespaced(ctx),
+		Provider: config.GetProviderNamespaced(ctx, config.WithTrailingDotPolicy(config.TrailingDotPolicy(*trailingDotPolicy)), config.WithDefaultTTL(*defaultTTL)),
 		// use the following WorkspaceStoreOption to enable the shared gRPC mode
 		// terraform.WithProviderRunner(terraform.NewSharedProvider(log, os.Getenv("TERRAFORM_NATIVE_PROVIDER_PATH"), terraform.WithNativeProviderArgs("-debuggable")))
 		WorkspaceStore:        terraform.NewWorkspaceStore(log),
-		SetupFn:               clients.TerraformSetupBuilder(*terraformVersion, *providerSource, *providerVersion),
+		SetupFn:               clients.TerraformSetupBuilder(*terraformVersion, *providerSource, *providerVersion, setupOpts...),
 		StartWebhooks:         *certsDir != "",
 		OperationTrackerStore: tjcontroller.NewOperationStore(log),
 	}
@@ -227,13 +242,14 @@ func main() {
 			Gate:                    crdGate,
 			MaxConcurrentReconciles: 1,
 		}), "Cannot setup CRD gate")
-		kingpin.FatalIfError(controllerCluster.SetupGated(mgr, clusterOpts), "Cannot setup cluster-scoped Dns-v2 controllers")
-		kingpin.FatalIfError(controllerNamespaced.SetupGated(mgr, namespacedOpts), "Cannot setup namespaced Dns-v2 controllers")
 	} else {
 		log.Info("Provider has missing RBAC permissions for watching CRDs, controller SafeStart capability will be disabled")
-		kingpin.FatalIfError(controllerCluster.Setup(mgr, clusterOpts), "Cannot setup cluster-scoped Dns-v2 controllers")
-		kingpin.FatalIfError(controllerNamespaced.Setup(mgr, namespacedOpts), "Cannot setup namespaced Dns-v2 controllers")
 	}
+	kingpin.FatalIfError(controller.SetupAll(mgr, controller.Options{
+		Cluster:    clusterOpts,
+		Namespaced: namespacedOpts,
+		Gated:      canSafeStart,
+	}, controller.Scope(*controllerScope)), "Cannot setup Dns-v2 controllers")
 
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
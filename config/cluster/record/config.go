@@ -5,6 +5,10 @@ import "github.com/crossplane/upjet/v2/pkg/config"
 const (
 	apiVersion = "v1alpha1"
 	shortGroup = "record"
+
+	// recordFQDNExtractor extracts the FQDN of a referenced record set, its
+	// Terraform ID, which ends with the trailing dot of its zone.
+	recordFQDNExtractor = `github.com/crossplane/upjet/v2/pkg/resource.ExtractParamPath("id",true)`
 )
 
 // Configure configures individual resources by adding custom ResourceConfigurators.
@@ -13,6 +17,10 @@ func Configure(p *config.Provider) {
 		r.ShortGroup = shortGroup
 		r.Kind = "CNAMERecord"
 		r.Version = apiVersion
+		r.References["cname"] = config.Reference{
+			TerraformName: "dns_a_record_set",
+			Extractor:     recordFQDNExtractor,
+		}
 	})
 
 	p.AddResourceConfigurator("dns_ptr_record", func(r *config.Resource) {
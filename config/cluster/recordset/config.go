@@ -5,6 +5,10 @@ import "github.com/crossplane/upjet/v2/pkg/config"
 const (
 	apiVersion = "v1alpha1"
 	shortGroup = "recordset"
+
+	// recordFQDNExtractor extracts the FQDN of a referenced record set, its
+	// Terraform ID, which ends with the trailing dot of its zone.
+	recordFQDNExtractor = `github.com/crossplane/upjet/v2/pkg/resource.ExtractParamPath("id",true)`
 )
 
 // Configure configures individual resources by adding custom ResourceConfigurators.
@@ -25,18 +29,30 @@ func Configure(p *config.Provider) {
 		r.ShortGroup = shortGroup
 		r.Kind = "MXRecordSet"
 		r.Version = apiVersion
+		r.References["mx.exchange"] = config.Reference{
+			TerraformName: "dns_a_record_set",
+			Extractor:     recordFQDNExtractor,
+		}
 	})
 
 	p.AddResourceConfigurator("dns_ns_record_set", func(r *config.Resource) {
 		r.ShortGroup = shortGroup
 		r.Kind = "NSRecordSet"
 		r.Version = apiVersion
+		r.References["nameservers"] = config.Reference{
+			TerraformName: "dns_a_record_set",
+			Extractor:     recordFQDNExtractor,
+		}
 	})
 
 	p.AddResourceConfigurator("dns_srv_record_set", func(r *config.Resource) {
 		r.ShortGroup = shortGroup
 		r.Kind = "SRVRecordSet"
 		r.Version = apiVersion
+		r.References["srv.target"] = config.Reference{
+			TerraformName: "dns_a_record_set",
+			Extractor:     recordFQDNExtractor,
+		}
 	})
 
 	p.AddResourceConfigurator("dns_txt_record_set", func(r *config.Resource) {
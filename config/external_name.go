@@ -1,21 +1,45 @@
 package config
 
-import "github.com/crossplane/upjet/v2/pkg/config"
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	// zoneApex is the zone file shorthand for the apex of the zone a record belongs to.
+	zoneApex = "@"
+
+	keyName = "name"
+	keyZone = "zone"
+
+	errOverrideOutsideZone  = "external name %q is not a record in zone %q"
+	errOverrideNameMismatch = "external name %q does not match name %q in zone %q"
+)
+
+// recordExternalName is the external name configuration of all record and
+// record set resources. Their Terraform ID is the FQDN of the record, that is
+// "<name>.<zone>", except for records at the zone apex which are identified by
+// the zone itself. An external name that is already fully qualified is used as
+// the ID as-is, which allows importing records with non-standard naming.
+var recordExternalName = recordIdentifier(config.TemplatedStringAsIdentifier("", "{{ .external_name }}.{{ .parameters.zone }}"))
 
 // terraformPluginSDKExternalNameConfigs contains all external name configurations for this
 // provider.
 var terraformPluginSDKExternalNameConfigs = map[string]config.ExternalName{
-	"dns_a_record_set":    config.TemplatedStringAsIdentifier("", "{{ .external_name }}.{{ .parameters.zone }}"),
-	"dns_aaaa_record_set": config.TemplatedStringAsIdentifier("", "{{ .external_name }}.{{ .parameters.zone }}"),
+	"dns_a_record_set":    recordExternalName,
+	"dns_aaaa_record_set": recordExternalName,
 }
 
 var terraformPluginFrameworkExternalNameConfigs = map[string]config.ExternalName{
-	"dns_mx_record_set":  config.TemplatedStringAsIdentifier("", "{{ .external_name }}.{{ .parameters.zone }}"),
-	"dns_ns_record_set":  config.TemplatedStringAsIdentifier("", "{{ .external_name }}.{{ .parameters.zone }}"),
-	"dns_srv_record_set": config.TemplatedStringAsIdentifier("", "{{ .external_name }}.{{ .parameters.zone }}"),
-	"dns_txt_record_set": config.TemplatedStringAsIdentifier("", "{{ .external_name }}.{{ .parameters.zone }}"),
-	"dns_cname_record":   config.TemplatedStringAsIdentifier("", "{{ .external_name }}.{{ .parameters.zone }}"),
-	"dns_ptr_record":     config.TemplatedStringAsIdentifier("", "{{ .external_name }}.{{ .parameters.zone }}"),
+	"dns_mx_record_set":  recordExternalName,
+	"dns_ns_record_set":  recordExternalName,
+	"dns_srv_record_set": recordExternalName,
+	"dns_txt_record_set": recordExternalName,
+	"dns_cname_record":   recordExternalName,
+	"dns_ptr_record":     ptrExternalName,
 }
 
 // cliReconciledExternalNameConfigs contains all external name configurations
@@ -23,12 +47,79 @@ var terraformPluginFrameworkExternalNameConfigs = map[string]config.ExternalName
 // architecture for this provider.
 var cliReconciledExternalNameConfigs = map[string]config.ExternalName{}
 
+// recordIdentifier wraps the given external name configuration so that "@", an
+// empty name and a name equal to the zone all refer to the zone apex. The
+// record name is dropped from the Terraform arguments in that case, since the
+// provider writes records without a name at the apex, and the ID becomes the
+// zone. Fully qualified external names override the computed ID.
+func recordIdentifier(parent config.ExternalName) config.ExternalName {
+	e := config.NewExternalNameFrom(parent,
+		config.WithSetIdentifierArgumentsFn(func(fn config.SetIdentifierArgumentsFn, base map[string]any, externalName string) {
+			fn(base, externalName)
+			name, _ := base[keyName].(string)
+			zone, _ := base[keyZone].(string)
+			if _, ok := base[keyName]; ok && IsZoneApex(name, zone) {
+				delete(base, keyName)
+			}
+		}),
+		config.WithGetIDFn(func(fn config.GetIDFn, ctx context.Context, externalName string, parameters map[string]any, terraformProviderConfig map[string]any) (string, error) {
+			zone, _ := parameters[keyZone].(string)
+			if zone != "" && IsZoneApex(externalName, zone) {
+				return zone, nil
+			}
+			if strings.HasSuffix(externalName, ".") {
+				return externalName, validateExternalNameOverride(externalName, parameters)
+			}
+			return fn(ctx, externalName, parameters, terraformProviderConfig)
+		}),
+		config.WithGetExternalNameFn(func(fn config.GetExternalNameFn, tfstate map[string]any) (string, error) {
+			id, _ := tfstate["id"].(string)
+			if zone, ok := tfstate[keyZone].(string); ok && id != "" && id == zone {
+				return zoneApex, nil
+			}
+			return fn(tfstate)
+		}),
+	)
+	// NewExternalNameFrom only carries over the functions of its parent.
+	e.OmittedFields = parent.OmittedFields
+	e.DisableNameInitializer = parent.DisableNameInitializer
+	e.IdentifierFields = parent.IdentifierFields
+	return e
+}
+
+// validateExternalNameOverride checks that a fully qualified external name
+// identifies a record in the zone of the resource and, when the name is set,
+// that record.
+func validateExternalNameOverride(externalName string, parameters map[string]any) error {
+	zone, _ := parameters[keyZone].(string)
+	if zone == "" {
+		return nil
+	}
+	if !strings.HasSuffix(strings.ToLower(externalName), "."+strings.ToLower(zone)) {
+		return errors.Errorf(errOverrideOutsideZone, externalName, zone)
+	}
+	if name, ok := parameters[keyName].(string); ok && !IsZoneApex(name, zone) && !strings.EqualFold(externalName, name+"."+zone) {
+		return errors.Errorf(errOverrideNameMismatch, externalName, name, zone)
+	}
+	return nil
+}
+
+// IsZoneApex reports whether the given record name refers to the apex of the
+// given zone: "@", an empty name or the zone itself.
+func IsZoneApex(name, zone string) bool {
+	if name == "" || name == zoneApex {
+		return true
+	}
+	return zone != "" && strings.EqualFold(strings.TrimSuffix(name, "."), strings.TrimSuffix(zone, "."))
+}
+
 // resourceConfigurator applies all external name configs listed in
 // the table terraformPluginSDKExternalNameConfigs,
 // cliReconciledExternalNameConfigs, and
 // terraformPluginFrameworkExternalNameConfigs and sets the version of
-// those resources to v1beta1.
-func resourceConfigurator() config.ResourceOption {
+// those resources to v1beta1. The trailing dot policy and the default TTL of
+// the given options apply to all of them.
+func resourceConfigurator(o *options) config.ResourceOption {
 	return func(r *config.Resource) {
 		// If an external name is configured for multiple architectures,
 		// Terraform Plugin Framework takes precedence over Terraform
@@ -44,6 +135,12 @@ func resourceConfigurator() config.ResourceOption {
 			return
 		}
 		r.Version = "v1beta1"
-		r.ExternalName = e
+		r.ExternalName = withTrailingDotPolicy(e, o.trailingDotPolicy)
+		if o.trailingDotPolicy == TrailingDotFQDN {
+			r.TerraformConversions = append(r.TerraformConversions, observedTrailingDotConversion{})
+		}
+		if o.defaultTTL > 0 {
+			r.TerraformConfigurationInjector = withDefaultTTL(o.defaultTTL)
+		}
 	}
 }
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestRecordExternalNameGetID(t *testing.T) {
+	type args struct {
+		externalName string
+		parameters   map[string]any
+	}
+	type want struct {
+		id  string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Apex": {
+			reason: "An @ external name should identify the zone apex.",
+			args: args{
+				externalName: "@",
+				parameters:   map[string]any{keyZone: "example.com.", keyName: "@"},
+			},
+			want: want{id: "example.com."},
+		},
+		"Empty": {
+			reason: "An empty external name should identify the zone apex.",
+			args: args{
+				externalName: "",
+				parameters:   map[string]any{keyZone: "example.com."},
+			},
+			want: want{id: "example.com."},
+		},
+		"ZoneEqual": {
+			reason: "An external name equal to the zone, with or without a trailing dot, should identify the zone apex.",
+			args: args{
+				externalName: "Example.com",
+				parameters:   map[string]any{keyZone: "example.com."},
+			},
+			want: want{id: "example.com."},
+		},
+		"Relative": {
+			reason: "A relative external name should be qualified with the zone.",
+			args: args{
+				externalName: "www",
+				parameters:   map[string]any{keyZone: "example.com.", keyName: "www"},
+			},
+			want: want{id: "www.example.com."},
+		},
+		"FQDNOverride": {
+			reason: "A fully qualified external name in the zone should be used as the ID as-is.",
+			args: args{
+				externalName: "legacy.example.com.",
+				parameters:   map[string]any{keyZone: "example.com."},
+			},
+			want: want{id: "legacy.example.com."},
+		},
+		"FQDNOverrideOutsideZone": {
+			reason: "A fully qualified external name outside the zone should be rejected.",
+			args: args{
+				externalName: "www.example.org.",
+				parameters:   map[string]any{keyZone: "example.com."},
+			},
+			want: want{
+				id:  "www.example.org.",
+				err: errors.Errorf(errOverrideOutsideZone, "www.example.org.", "example.com."),
+			},
+		},
+		"FQDNOverrideNameMismatch": {
+			reason: "A fully qualified external name that does not match the name should be rejected.",
+			args: args{
+				externalName: "www.example.com.",
+				parameters:   map[string]any{keyZone: "example.com.", keyName: "mail"},
+			},
+			want: want{
+				id:  "www.example.com.",
+				err: errors.Errorf(errOverrideNameMismatch, "www.example.com.", "mail", "example.com."),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			id, err := recordExternalName.GetIDFn(context.Background(), tc.args.externalName, tc.args.parameters, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetIDFn(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.id, id); diff != "" {
+				t.Errorf("\n%s\nGetIDFn(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRecordExternalNameSetIdentifierArguments(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		base   map[string]any
+		want   map[string]any
+	}{
+		"Apex": {
+			reason: "An @ name should be dropped from the Terraform arguments.",
+			base:   map[string]any{keyZone: "example.com.", keyName: "@"},
+			want:   map[string]any{keyZone: "example.com."},
+		},
+		"Empty": {
+			reason: "An empty name should be dropped from the Terraform arguments.",
+			base:   map[string]any{keyZone: "example.com.", keyName: ""},
+			want:   map[string]any{keyZone: "example.com."},
+		},
+		"ZoneEqual": {
+			reason: "A name equal to the zone should be dropped from the Terraform arguments.",
+			base:   map[string]any{keyZone: "example.com.", keyName: "example.com"},
+			want:   map[string]any{keyZone: "example.com."},
+		},
+		"Relative": {
+			reason: "A relative name should be kept.",
+			base:   map[string]any{keyZone: "example.com.", keyName: "www"},
+			want:   map[string]any{keyZone: "example.com.", keyName: "www"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			recordExternalName.SetIdentifierArgumentFn(tc.base, "")
+			if diff := cmp.Diff(tc.want, tc.base); diff != "" {
+				t.Errorf("\n%s\nSetIdentifierArgumentFn(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRecordExternalNameGetExternalName(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		tfstate map[string]any
+		want    string
+	}{
+		"Apex": {
+			reason:  "A record identified by its zone should have the @ external name.",
+			tfstate: map[string]any{"id": "example.com.", keyZone: "example.com."},
+			want:    zoneApex,
+		},
+		"Relative": {
+			reason:  "A record below the zone should have its name as the external name.",
+			tfstate: map[string]any{"id": "www.example.com.", keyZone: "example.com."},
+			want:    "www",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := recordExternalName.GetExternalNameFn(tc.tfstate)
+			if err != nil {
+				t.Fatalf("\n%s\nGetExternalNameFn(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetExternalNameFn(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"testing"
+
+	upjetresource "github.com/crossplane/upjet/v2/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+
+	clusterrecordset "github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1"
+	"github.com/dana-team/provider-dns-v2/apis/namespaced/recordset/v1alpha1"
+)
+
+func to[T any](v T) *T { return &v }
+
+// TestInitProviderTTL checks that a TTL set in initProvider is sent on
+// creation, ignored while only initProvider sets it, and settles on the
+// value the DNS server reports through late initialization.
+func TestInitProviderTTL(t *testing.T) {
+	type want struct {
+		created     any
+		ignored     []string
+		lateInit    bool
+		forProvider *int64
+		settled     bool
+	}
+
+	cases := map[string]struct {
+		reason   string
+		rs       upjetresource.Terraformed
+		ttl      func(upjetresource.Terraformed) *int64
+		observed string
+		want     want
+	}{
+		"NamespacedInitProvider": {
+			reason: "A TTL only in initProvider should be sent on creation, ignored afterwards and late-initialized to the value of the server.",
+			rs: func() upjetresource.Terraformed {
+				rs := &v1alpha1.ARecordSet{}
+				rs.Spec.InitProvider.TTL = to[int64](300)
+				return rs
+			}(),
+			ttl:      func(tr upjetresource.Terraformed) *int64 { return tr.(*v1alpha1.ARecordSet).Spec.ForProvider.TTL },
+			observed: `{"ttl":600}`,
+			want:     want{created: float64(300), ignored: []string{keyTTL}, lateInit: true, forProvider: to[int64](600), settled: true},
+		},
+		"ClusterInitProvider": {
+			reason: "Cluster scoped record sets should handle an initProvider TTL like namespaced ones.",
+			rs: func() upjetresource.Terraformed {
+				rs := &clusterrecordset.ARecordSet{}
+				rs.Spec.InitProvider.TTL = to[int64](300)
+				return rs
+			}(),
+			ttl: func(tr upjetresource.Terraformed) *int64 {
+				return tr.(*clusterrecordset.ARecordSet).Spec.ForProvider.TTL
+			},
+			observed: `{"ttl":600}`,
+			want:     want{created: float64(300), ignored: []string{keyTTL}, lateInit: true, forProvider: to[int64](600), settled: true},
+		},
+		"ForProvider": {
+			reason: "A TTL in forProvider should neither be ignored nor overwritten by the value of the server, so it is re-applied.",
+			rs: func() upjetresource.Terraformed {
+				rs := &v1alpha1.ARecordSet{}
+				rs.Spec.ForProvider.TTL = to[int64](300)
+				return rs
+			}(),
+			ttl:      func(tr upjetresource.Terraformed) *int64 { return tr.(*v1alpha1.ARecordSet).Spec.ForProvider.TTL },
+			observed: `{"ttl":600}`,
+			want:     want{created: float64(300), ignored: []string{}, forProvider: to[int64](300)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			params, err := tc.rs.GetMergedParameters(true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.created, params[keyTTL]); diff != "" {
+				t.Errorf("\n%s\nGetMergedParameters(...): -want ttl, +got ttl:\n%s", tc.reason, diff)
+			}
+
+			ignored := ignoreChanges(t, tc.rs)
+			if diff := cmp.Diff(tc.want.ignored, ignored); diff != "" {
+				t.Errorf("\n%s\nGetTerraformIgnoreChanges(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			changed, err := tc.rs.LateInitialize([]byte(tc.observed))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.lateInit, changed); diff != "" {
+				t.Errorf("\n%s\nLateInitialize(...): -want changed, +got changed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.forProvider, tc.ttl(tc.rs)); diff != "" {
+				t.Errorf("\n%s\nLateInitialize(...): -want forProvider.ttl, +got forProvider.ttl:\n%s", tc.reason, diff)
+			}
+
+			// Once late-initialized, forProvider matches the server, so the
+			// TTL is no longer ignored and does not change again.
+			changed, err = tc.rs.LateInitialize([]byte(tc.observed))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed {
+				t.Errorf("\n%s\nLateInitialize(...): want a second late initialization to change nothing", tc.reason)
+			}
+			if tc.want.settled {
+				if diff := cmp.Diff([]string{}, ignoreChanges(t, tc.rs)); diff != "" {
+					t.Errorf("\n%s\nGetTerraformIgnoreChanges(...): -want after late initialization, +got:\n%s", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
+
+// ignoreChanges returns the ignore_changes upjet derives for the given
+// resource.
+func ignoreChanges(t *testing.T, tr upjetresource.Terraformed) []string {
+	t.Helper()
+	params, err := tr.GetParameters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	initParams, err := tr.GetInitParameters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return upjetresource.GetTerraformIgnoreChanges(params, initParams)
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/crossplane/upjet/v2/pkg/config"
+)
+
+// TrailingDotPolicy controls how trailing dots of the domain names in record
+// and record set arguments are handled.
+type TrailingDotPolicy string
+
+const (
+	// TrailingDotPreserve passes domain names to the provider as written.
+	TrailingDotPreserve TrailingDotPolicy = "preserve"
+	// TrailingDotFQDN appends a trailing dot to the zone and to every domain
+	// name value, such as CNAME, MX, NS and SRV targets, that lacks one, so
+	// that relative and fully qualified forms do not diff against each other.
+	TrailingDotFQDN TrailingDotPolicy = "fqdn"
+)
+
+// withTrailingDotPolicy returns the given external name configuration with
+// domain name arguments canonicalized according to the given policy.
+func withTrailingDotPolicy(parent config.ExternalName, policy TrailingDotPolicy) config.ExternalName {
+	if policy != TrailingDotFQDN {
+		return parent
+	}
+	e := parent
+	e.SetIdentifierArgumentFn = func(base map[string]any, externalName string) {
+		parent.SetIdentifierArgumentFn(base, externalName)
+		canonicalizeDomainNames(base)
+	}
+	return e
+}
+
+// canonicalizeDomainNames appends a trailing dot to the zone and to every
+// domain name value in the given Terraform arguments that lacks one.
+func canonicalizeDomainNames(base map[string]any) {
+	if zone, ok := base[keyZone].(string); ok {
+		base[keyZone] = fqdn(zone)
+	}
+	for block, fields := range domainNameFields {
+		objects := []any{base}
+		if block != "" {
+			objects, _ = base[block].([]any)
+		}
+		for _, o := range objects {
+			m, ok := o.(map[string]any)
+			if !ok {
+				continue
+			}
+			for _, field := range fields {
+				switch v := m[field].(type) {
+				case string:
+					m[field] = fqdn(v)
+				case []any:
+					for i, e := range v {
+						if s, ok := e.(string); ok {
+							v[i] = fqdn(s)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// fqdn appends a trailing dot to the given non-empty domain name if missing.
+func fqdn(name string) string {
+	if name == "" || strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
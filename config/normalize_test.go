@@ -0,0 +1,163 @@
+package config
+
+import (
+	"testing"
+
+	ujconfig "github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestTrailingDotPolicyArguments(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		policy TrailingDotPolicy
+		base   map[string]any
+		want   map[string]any
+	}{
+		"PreserveRelative": {
+			reason: "The preserve policy should pass relative names as written.",
+			policy: TrailingDotPreserve,
+			base:   map[string]any{keyZone: "example.com", keyName: "www", "cname": "target.example.com"},
+			want:   map[string]any{keyZone: "example.com", keyName: "www", "cname": "target.example.com"},
+		},
+		"FQDNCNAME": {
+			reason: "The fqdn policy should qualify the zone and a CNAME target.",
+			policy: TrailingDotFQDN,
+			base:   map[string]any{keyZone: "example.com", keyName: "www", "cname": "target.example.com"},
+			want:   map[string]any{keyZone: "example.com.", keyName: "www", "cname": "target.example.com."},
+		},
+		"FQDNAlreadyQualified": {
+			reason: "The fqdn policy should leave fully qualified names unchanged.",
+			policy: TrailingDotFQDN,
+			base:   map[string]any{keyZone: "example.com.", keyName: "www", "ptr": "host.example.com."},
+			want:   map[string]any{keyZone: "example.com.", keyName: "www", "ptr": "host.example.com."},
+		},
+		"FQDNNameservers": {
+			reason: "The fqdn policy should qualify every NS nameserver.",
+			policy: TrailingDotFQDN,
+			base:   map[string]any{keyZone: "example.com.", keyName: "sub", "nameservers": []any{"ns1.example.com", "ns2.example.com."}},
+			want:   map[string]any{keyZone: "example.com.", keyName: "sub", "nameservers": []any{"ns1.example.com.", "ns2.example.com."}},
+		},
+		"FQDNMXAndSRV": {
+			reason: "The fqdn policy should qualify MX exchanges and SRV targets.",
+			policy: TrailingDotFQDN,
+			base: map[string]any{
+				keyZone: "example.com.",
+				"mx":    []any{map[string]any{"exchange": "mail.example.com", "preference": float64(10)}},
+				"srv":   []any{map[string]any{"target": "sip.example.com", "port": float64(5060)}},
+			},
+			want: map[string]any{
+				keyZone: "example.com.",
+				"mx":    []any{map[string]any{"exchange": "mail.example.com.", "preference": float64(10)}},
+				"srv":   []any{map[string]any{"target": "sip.example.com.", "port": float64(5060)}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			withTrailingDotPolicy(recordExternalName, tc.policy).SetIdentifierArgumentFn(tc.base, "www")
+			if diff := cmp.Diff(tc.want, tc.base); diff != "" {
+				t.Errorf("\n%s\nSetIdentifierArgumentFn(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObservedTrailingDotConversion(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		mode   ujconfig.Mode
+		params map[string]any
+		want   map[string]any
+	}{
+		"FromTerraform": {
+			reason: "The observed state should be canonicalized.",
+			mode:   ujconfig.FromTerraform,
+			params: map[string]any{keyZone: "example.com", "cname": "target.example.com"},
+			want:   map[string]any{keyZone: "example.com.", "cname": "target.example.com."},
+		},
+		"ToTerraform": {
+			reason: "The arguments are canonicalized by the external name configuration instead.",
+			mode:   ujconfig.ToTerraform,
+			params: map[string]any{keyZone: "example.com", "cname": "target.example.com"},
+			want:   map[string]any{keyZone: "example.com", "cname": "target.example.com"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := observedTrailingDotConversion{}.Convert(tc.params, nil, tc.mode)
+			if err != nil {
+				t.Fatalf("\n%s\nConvert(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeValue(t *testing.T) {
+	mxType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"exchange":   tftypes.String,
+		"preference": tftypes.Number,
+	}}
+	recordType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":          tftypes.String,
+		"zone":        tftypes.String,
+		"name":        tftypes.String,
+		"cname":       tftypes.String,
+		"nameservers": tftypes.Set{ElementType: tftypes.String},
+		"mx":          tftypes.Set{ElementType: mxType},
+	}}
+	record := func(zone, cname string, nameservers []string, exchange string) tftypes.Value {
+		ns := make([]tftypes.Value, len(nameservers))
+		for i, n := range nameservers {
+			ns[i] = tftypes.NewValue(tftypes.String, n)
+		}
+		return tftypes.NewValue(recordType, map[string]tftypes.Value{
+			"id":          tftypes.NewValue(tftypes.String, "www.example.com."),
+			"zone":        tftypes.NewValue(tftypes.String, zone),
+			"name":        tftypes.NewValue(tftypes.String, "www"),
+			"cname":       tftypes.NewValue(tftypes.String, cname),
+			"nameservers": tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, ns),
+			"mx": tftypes.NewValue(tftypes.Set{ElementType: mxType}, []tftypes.Value{
+				tftypes.NewValue(mxType, map[string]tftypes.Value{
+					"exchange":   tftypes.NewValue(tftypes.String, exchange),
+					"preference": tftypes.NewValue(tftypes.Number, 10),
+				}),
+			}),
+		})
+	}
+
+	cases := map[string]struct {
+		reason string
+		value  tftypes.Value
+		want   tftypes.Value
+	}{
+		"Relative": {
+			reason: "Relative domain names of the state should be qualified, other attributes left as is.",
+			value:  record("example.com", "target.example.com", []string{"ns1.example.com"}, "mail.example.com"),
+			want:   record("example.com.", "target.example.com.", []string{"ns1.example.com."}, "mail.example.com."),
+		},
+		"FQDN": {
+			reason: "Fully qualified domain names of the state should be unchanged.",
+			value:  record("example.com.", "target.example.com.", []string{"ns1.example.com."}, "mail.example.com."),
+			want:   record("example.com.", "target.example.com.", []string{"ns1.example.com."}, "mail.example.com."),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := canonicalizeValue(tc.value)
+			if err != nil {
+				t.Fatalf("\n%s\ncanonicalizeValue(...): unexpected error: %v", tc.reason, err)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("\n%s\ncanonicalizeValue(...): want %s, got %s", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
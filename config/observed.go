@@ -0,0 +1,164 @@
+package config
+
+import (
+	"context"
+
+	"github.com/crossplane/upjet/v2/pkg/config"
+	fwprovider "github.com/hashicorp/terraform-plugin-framework/provider"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const (
+	errCanonicalizeState  = "Cannot canonicalize the domain names of the state"
+	errImportNotSupported = "Resource does not support import"
+)
+
+// CanonicalizeObserved returns the given Terraform Plugin Framework provider
+// with the domain names of the states its resources produce canonicalized
+// according to the given policy, like the arguments they are configured with.
+// The observed record, its late-initialized parameters and its diff against
+// the arguments then all use the same form.
+func CanonicalizeObserved(p fwprovider.Provider, policy TrailingDotPolicy) fwprovider.Provider {
+	if policy != TrailingDotFQDN {
+		return p
+	}
+	return &canonicalProvider{Provider: p}
+}
+
+// canonicalProvider wraps the resources of a framework provider so that the
+// domain names of their states are fully qualified.
+type canonicalProvider struct {
+	fwprovider.Provider
+}
+
+func (p *canonicalProvider) Resources(ctx context.Context) []func() fwresource.Resource {
+	resources := p.Provider.Resources(ctx)
+	wrapped := make([]func() fwresource.Resource, len(resources))
+	for i, newResource := range resources {
+		wrapped[i] = func() fwresource.Resource {
+			return &canonicalResource{Resource: newResource()}
+		}
+	}
+	return wrapped
+}
+
+// canonicalResource canonicalizes the state a framework resource returns from
+// creates, reads and updates. The record resources of the provider configure
+// their DNS client and support imports, which are passed through.
+type canonicalResource struct {
+	fwresource.Resource
+}
+
+var (
+	_ fwresource.ResourceWithConfigure   = &canonicalResource{}
+	_ fwresource.ResourceWithImportState = &canonicalResource{}
+)
+
+func (r *canonicalResource) Configure(ctx context.Context, req fwresource.ConfigureRequest, resp *fwresource.ConfigureResponse) {
+	if c, ok := r.Resource.(fwresource.ResourceWithConfigure); ok {
+		c.Configure(ctx, req, resp)
+	}
+}
+
+func (r *canonicalResource) ImportState(ctx context.Context, req fwresource.ImportStateRequest, resp *fwresource.ImportStateResponse) {
+	if i, ok := r.Resource.(fwresource.ResourceWithImportState); ok {
+		i.ImportState(ctx, req, resp)
+		return
+	}
+	resp.Diagnostics.AddError(errImportNotSupported, "")
+}
+
+func (r *canonicalResource) Create(ctx context.Context, req fwresource.CreateRequest, resp *fwresource.CreateResponse) {
+	r.Resource.Create(ctx, req, resp)
+	canonicalizeState(&resp.State, resp.Diagnostics.AddError)
+}
+
+func (r *canonicalResource) Read(ctx context.Context, req fwresource.ReadRequest, resp *fwresource.ReadResponse) {
+	r.Resource.Read(ctx, req, resp)
+	canonicalizeState(&resp.State, resp.Diagnostics.AddError)
+}
+
+func (r *canonicalResource) Update(ctx context.Context, req fwresource.UpdateRequest, resp *fwresource.UpdateResponse) {
+	r.Resource.Update(ctx, req, resp)
+	canonicalizeState(&resp.State, resp.Diagnostics.AddError)
+}
+
+// canonicalizeState appends a trailing dot to the zone and the domain names
+// of the given state, reporting a failure through the given function.
+func canonicalizeState(state *tfsdk.State, addError func(summary, detail string)) {
+	if state.Raw.IsNull() || !state.Raw.IsKnown() {
+		return
+	}
+	raw, err := canonicalizeValue(state.Raw)
+	if err != nil {
+		addError(errCanonicalizeState, err.Error())
+		return
+	}
+	state.Raw = raw
+}
+
+// canonicalizeValue appends a trailing dot to the zone and the domain name
+// values of the given Terraform value of a record or record set.
+func canonicalizeValue(v tftypes.Value) (tftypes.Value, error) {
+	return tftypes.Transform(v, func(path *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if !isDomainNamePath(path) || !v.Type().Is(tftypes.String) || !v.IsKnown() || v.IsNull() {
+			return v, nil
+		}
+		var s string
+		if err := v.As(&s); err != nil {
+			return v, err
+		}
+		return tftypes.NewValue(tftypes.String, fqdn(s)), nil
+	})
+}
+
+// isDomainNamePath reports whether the given path refers to the zone or to a
+// domain name listed in domainNameFields, either directly or as an element of
+// a set of domain names.
+func isDomainNamePath(path *tftypes.AttributePath) bool {
+	steps := path.Steps()
+	if len(steps) == 0 {
+		return false
+	}
+	first, ok := steps[0].(tftypes.AttributeName)
+	if !ok {
+		return false
+	}
+	switch len(steps) {
+	case 1:
+		return string(first) == keyZone || isDomainNameField("", string(first))
+	case 2:
+		_, element := steps[1].(tftypes.ElementKeyValue)
+		return element && isDomainNameField("", string(first))
+	case 3:
+		field, ok := steps[2].(tftypes.AttributeName)
+		return ok && isDomainNameField(string(first), string(field))
+	default:
+		return false
+	}
+}
+
+// isDomainNameField reports whether the given field of the given block holds
+// domain names.
+func isDomainNameField(block, field string) bool {
+	for _, f := range domainNameFields[block] {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// observedTrailingDotConversion canonicalizes the domain names of the state of
+// Terraform Plugin SDK resources, whose observed state upjet converts through
+// the Terraform conversions of the resource.
+type observedTrailingDotConversion struct{}
+
+func (observedTrailingDotConversion) Convert(params map[string]any, _ *config.Resource, mode config.Mode) (map[string]any, error) {
+	if mode == config.FromTerraform {
+		canonicalizeDomainNames(params)
+	}
+	return params, nil
+}
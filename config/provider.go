@@ -4,6 +4,7 @@ import (
 	"context"
 	// Note(turkenh): we are importing this to embed provider schema document
 	_ "embed"
+	"sort"
 
 	"github.com/crossplane/upjet/v2/pkg/registry/reference"
 	"github.com/hashicorp/terraform-provider-dns/xpprovider"
@@ -28,9 +29,43 @@ var providerSchema string
 //go:embed provider-metadata.yaml
 var providerMetadata string
 
+// An Option configures the provider configuration.
+type Option func(*options)
+
+type options struct {
+	trailingDotPolicy TrailingDotPolicy
+	defaultTTL        int
+}
+
+// WithTrailingDotPolicy sets the policy applied to the trailing dots of the
+// domain names in record and record set arguments.
+func WithTrailingDotPolicy(p TrailingDotPolicy) Option {
+	return func(o *options) {
+		o.trailingDotPolicy = p
+	}
+}
+
+// WithDefaultTTL sets the TTL, in seconds, of the records and record sets that
+// do not set one. The default of the Terraform provider, 3600, is used when
+// the given TTL is not positive.
+func WithDefaultTTL(ttl int) Option {
+	return func(o *options) {
+		o.defaultTTL = ttl
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{trailingDotPolicy: TrailingDotPreserve}
+	for _, fn := range opts {
+		fn(o)
+	}
+	return o
+}
+
 // GetProvider returns provider configuration
-func GetProvider(ctx context.Context) *ujconfig.Provider {
+func GetProvider(ctx context.Context, opts ...Option) *ujconfig.Provider {
 	fwProvider, p := xpprovider.GetProvider(ctx)
+	o := newOptions(opts)
 
 	pc := ujconfig.NewProvider([]byte(providerSchema), resourcePrefix, modulePath, []byte(providerMetadata),
 		ujconfig.WithRootGroup("dns-v2.crossplane.io"),
@@ -39,7 +74,7 @@ func GetProvider(ctx context.Context) *ujconfig.Provider {
 		ujconfig.WithTerraformPluginSDKIncludeList(resourceList(terraformPluginSDKExternalNameConfigs)),
 		ujconfig.WithTerraformPluginFrameworkIncludeList(resourceList(terraformPluginFrameworkExternalNameConfigs)),
 		ujconfig.WithDefaultResourceOptions(
-			resourceConfigurator(),
+			resourceConfigurator(o),
 		),
 		ujconfig.WithReferenceInjectors([]ujconfig.ReferenceInjector{reference.NewInjector(modulePath)}),
 		ujconfig.WithFeaturesPackage("internal/features"),
@@ -60,8 +95,9 @@ func GetProvider(ctx context.Context) *ujconfig.Provider {
 }
 
 // GetProviderNamespaced returns the namespaced provider configuration
-func GetProviderNamespaced(ctx context.Context) *ujconfig.Provider {
+func GetProviderNamespaced(ctx context.Context, opts ...Option) *ujconfig.Provider {
 	fwProvider, p := xpprovider.GetProvider(ctx)
+	o := newOptions(opts)
 
 	pc := ujconfig.NewProvider([]byte(providerSchema), namespacedResourcePrefix, modulePath, []byte(providerMetadata),
 		ujconfig.WithRootGroup("dns-v2.m.crossplane.io"),
@@ -70,7 +106,7 @@ func GetProviderNamespaced(ctx context.Context) *ujconfig.Provider {
 		ujconfig.WithTerraformPluginSDKIncludeList(resourceList(terraformPluginSDKExternalNameConfigs)),
 		ujconfig.WithTerraformPluginFrameworkIncludeList(resourceList(terraformPluginFrameworkExternalNameConfigs)),
 		ujconfig.WithDefaultResourceOptions(
-			resourceConfigurator(),
+			resourceConfigurator(o),
 		),
 		ujconfig.WithReferenceInjectors([]ujconfig.ReferenceInjector{reference.NewInjector(modulePath)}),
 		ujconfig.WithFeaturesPackage("internal/features"),
@@ -88,7 +124,26 @@ func GetProviderNamespaced(ctx context.Context) *ujconfig.Provider {
 	return pc
 }
 
-// resourceList returns the list of resources that have external
+// IncludeLists are the include lists the provider is configured with for
+// each Terraform architecture. Entries are the regular expressions passed to
+// upjet, e.g. "dns_a_record_set$".
+type IncludeLists struct {
+	CLI                      []string `json:"cli"`
+	TerraformPluginSDK       []string `json:"terraformPluginSDK"`
+	TerraformPluginFramework []string `json:"terraformPluginFramework"`
+}
+
+// GetIncludeLists returns the resource include lists of the provider, so that
+// tooling can introspect which resources are reconciled by which architecture.
+func GetIncludeLists() IncludeLists {
+	return IncludeLists{
+		CLI:                      resourceList(cliReconciledExternalNameConfigs),
+		TerraformPluginSDK:       resourceList(terraformPluginSDKExternalNameConfigs),
+		TerraformPluginFramework: resourceList(terraformPluginFrameworkExternalNameConfigs),
+	}
+}
+
+// resourceList returns the sorted list of resources that have external
 // name configured in the specified table.
 func resourceList(t map[string]ujconfig.ExternalName) []string {
 	l := make([]string, len(t))
@@ -98,5 +153,6 @@ func resourceList(t map[string]ujconfig.ExternalName) []string {
 		l[i] = n + "$"
 		i++
 	}
+	sort.Strings(l)
 	return l
 }
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/crossplane/upjet/v2/pkg/config"
+	"github.com/miekg/dns"
+)
+
+// ptrExternalName is the external name configuration of PTR records. In
+// addition to record names, the name and the external name of a PTR record
+// may be the forward IPv4 or IPv6 address the record is for, in which case
+// the reverse name under in-addr.arpa or ip6.arpa is derived from it.
+var ptrExternalName = ptrIdentifier(recordExternalName)
+
+// ptrIdentifier wraps the given external name configuration so that IP
+// addresses are replaced by their reverse names: the name argument by the
+// reverse name relative to the zone, and the external name by the fully
+// qualified reverse name.
+func ptrIdentifier(parent config.ExternalName) config.ExternalName {
+	e := parent
+	e.SetIdentifierArgumentFn = func(base map[string]any, externalName string) {
+		parent.SetIdentifierArgumentFn(base, externalName)
+		reverseNameArgument(base)
+	}
+	e.GetIDFn = func(ctx context.Context, externalName string, parameters map[string]any, terraformProviderConfig map[string]any) (string, error) {
+		if reverse, ok := reverseAddr(externalName); ok {
+			externalName = reverse
+		}
+		if name, ok := parameters[keyName].(string); ok {
+			if _, ok := reverseAddr(name); ok {
+				params := make(map[string]any, len(parameters))
+				for k, v := range parameters {
+					params[k] = v
+				}
+				reverseNameArgument(params)
+				parameters = params
+			}
+		}
+		return parent.GetIDFn(ctx, externalName, parameters, terraformProviderConfig)
+	}
+	return e
+}
+
+// reverseNameArgument replaces an IP address in the name argument with its
+// reverse name relative to the zone. A reverse name outside of the zone is
+// kept fully qualified, which the ID validation then rejects.
+func reverseNameArgument(base map[string]any) {
+	name, _ := base[keyName].(string)
+	reverse, ok := reverseAddr(name)
+	if !ok {
+		return
+	}
+	zone, _ := base[keyZone].(string)
+	if zone == "" {
+		base[keyName] = reverse
+		return
+	}
+	suffix := "." + strings.ToLower(fqdn(zone))
+	if strings.HasSuffix(reverse, suffix) {
+		reverse = strings.TrimSuffix(reverse, suffix)
+	}
+	base[keyName] = reverse
+}
+
+// reverseAddr returns the fully qualified reverse name of the given IP
+// address, with IPv6 addresses expanded to their nibbles.
+func reverseAddr(value string) (string, bool) {
+	if net.ParseIP(value) == nil {
+		return "", false
+	}
+	reverse, err := dns.ReverseAddr(value)
+	return reverse, err == nil
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"github.com/crossplane/upjet/v2/pkg/config"
+)
+
+const keyTTL = "ttl"
+
+// withDefaultTTL returns a configuration injector that sets the TTL of records
+// and record sets that leave it unset in both forProvider and initProvider to
+// the given value, instead of the default of the Terraform provider. The TTL
+// is sent on every apply, so the echoed TTL matches it and does not diff.
+func withDefaultTTL(ttl int) config.ConfigurationInjector {
+	return func(_ map[string]any, tfMap map[string]any) error {
+		if _, ok := tfMap[keyTTL]; !ok {
+			// Numbers are float64 in the Terraform parameters.
+			tfMap[keyTTL] = float64(ttl)
+		}
+		return nil
+	}
+}
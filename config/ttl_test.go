@@ -0,0 +1,120 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	ujconfig "github.com/crossplane/upjet/v2/pkg/config"
+	upjetresource "github.com/crossplane/upjet/v2/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+
+	clusterrecordset "github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1"
+	"github.com/dana-team/provider-dns-v2/apis/namespaced/recordset/v1alpha1"
+)
+
+func TestWithDefaultTTL(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		rs     upjetresource.Terraformed
+		want   any
+	}{
+		"Unset": {
+			reason: "A record set without a TTL should get the default TTL.",
+			rs:     &v1alpha1.ARecordSet{},
+			want:   float64(120),
+		},
+		"ForProvider": {
+			reason: "A TTL in forProvider should override the default TTL.",
+			rs: func() upjetresource.Terraformed {
+				rs := &v1alpha1.TXTRecordSet{}
+				rs.Spec.ForProvider.TTL = to[float64](300)
+				return rs
+			}(),
+			want: float64(300),
+		},
+		"InitProvider": {
+			reason: "A TTL in initProvider should override the default TTL.",
+			rs: func() upjetresource.Terraformed {
+				rs := &v1alpha1.MXRecordSet{}
+				rs.Spec.InitProvider.TTL = to[float64](300)
+				return rs
+			}(),
+			want: float64(300),
+		},
+		"ZeroTTL": {
+			reason: "A TTL of zero set in forProvider should override the default TTL.",
+			rs: func() upjetresource.Terraformed {
+				rs := &v1alpha1.ARecordSet{}
+				rs.Spec.ForProvider.TTL = to[int64](0)
+				return rs
+			}(),
+			want: float64(0),
+		},
+		"ClusterUnset": {
+			reason: "A cluster scoped record set without a TTL should get the default TTL.",
+			rs:     &clusterrecordset.AAAARecordSet{},
+			want:   float64(120),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			params, err := tc.rs.GetMergedParameters(true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := withDefaultTTL(120)(nil, params); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, params[keyTTL]); diff != "" {
+				t.Errorf("\n%s\nwithDefaultTTL(...): -want ttl, +got ttl:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefaultTTLConfigured(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		opts   []Option
+		want   any
+	}{
+		"DefaultTTL": {
+			reason: "Every record and record set kind should get the default TTL.",
+			opts:   []Option{WithDefaultTTL(120)},
+			want:   float64(120),
+		},
+		"NoDefaultTTL": {
+			reason: "Without a default TTL, the TTL should be left to the Terraform provider.",
+		},
+		"NegativeDefaultTTL": {
+			reason: "A default TTL that is not positive should leave the TTL to the Terraform provider.",
+			opts:   []Option{WithDefaultTTL(-1)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			for _, scope := range []struct {
+				name        string
+				getProvider func(context.Context, ...Option) *ujconfig.Provider
+			}{{"cluster", GetProvider}, {"namespaced", GetProviderNamespaced}} {
+				p := scope.getProvider(context.Background(), tc.opts...)
+				if len(p.Resources) == 0 {
+					t.Fatalf("%s: want resources to be configured", scope.name)
+				}
+				for tfName, r := range p.Resources {
+					params := map[string]any{}
+					if r.TerraformConfigurationInjector != nil {
+						if err := r.TerraformConfigurationInjector(nil, params); err != nil {
+							t.Fatal(err)
+						}
+					}
+					if diff := cmp.Diff(tc.want, params[keyTTL]); diff != "" {
+						t.Errorf("\n%s\n%s %s: -want ttl, +got ttl:\n%s", tc.reason, scope.name, tfName, diff)
+					}
+				}
+			}
+		})
+	}
+}
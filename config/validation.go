@@ -0,0 +1,192 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxNameLength is the longest domain name in presentation format, without
+	// the trailing dot, that fits in the 255 octets RFC 1035 allows on the wire.
+	MaxNameLength = 253
+	// MaxLabelLength is the longest label RFC 1035 allows.
+	MaxLabelLength = 63
+	// MaxTTL is the largest TTL RFC 2181 allows.
+	MaxTTL = 1<<31 - 1
+	// maxCharacterStringLength is the longest character-string RFC 1035 allows.
+	// Longer TXT values are split into several character-strings by the provider.
+	maxCharacterStringLength = 255
+	// maxRDataLength is the longest RDATA RFC 1035 allows.
+	maxRDataLength = 65535
+
+	keyTXT = "txt"
+
+	errNameTooLong  = "%s %q is %d characters long, exceeding the maximum of %d"
+	errLabelTooLong = "label %q of %s %q is %d characters long, exceeding the maximum of %d"
+	errEmptyLabel   = "%s %q contains an empty label"
+	errTXTTooLong   = "txt value of %d octets exceeds the maximum record data length of %d octets"
+
+	errInvalidCharacter = "label %q contains %q, only letters, digits, hyphens and underscores are allowed"
+	errHyphen           = "label %q must not start or end with a hyphen"
+	errLabelLength      = "label %q is %d characters long, it must be between 1 and %d"
+	errNameLength       = "name %q is %d characters long, exceeding the maximum of %d"
+	errWildcard         = "a wildcard is only allowed as the leftmost label"
+	errTTLRange         = "ttl must be between 0 and %d"
+
+	// wildcard is the label of a wildcard record.
+	wildcard = "*"
+)
+
+// domainNameFields are the Terraform arguments of the record and record set
+// resources holding a domain name, keyed by the argument of the nested block
+// they are part of, if any.
+var domainNameFields = map[string][]string{
+	"":    {"cname", "ptr", "nameservers"},
+	"mx":  {"exchange"},
+	"srv": {"target"},
+}
+
+// ValidateRecord checks the record name and every domain name and TXT value in
+// the given Terraform arguments of a record or record set against the length
+// limits of RFC 1035. It is meant for records about to be created or updated;
+// records that fail it must still be observable and deletable.
+func ValidateRecord(parameters map[string]any) error {
+	zone, _ := parameters[keyZone].(string)
+	name, _ := parameters[keyName].(string)
+	fqdn := zone
+	if !IsZoneApex(name, zone) {
+		fqdn = name + "." + zone
+	}
+	if err := checkNameLength(keyName, fqdn); err != nil {
+		return err
+	}
+
+	for block, fields := range domainNameFields {
+		objects := []any{parameters}
+		if block != "" {
+			objects, _ = parameters[block].([]any)
+		}
+		for _, o := range objects {
+			m, _ := o.(map[string]any)
+			for _, field := range fields {
+				for _, v := range stringValues(m[field]) {
+					if err := checkNameLength(field, v); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	for _, txt := range stringValues(parameters[keyTXT]) {
+		if err := validateTXT(txt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkNameLength checks the given domain name against the name and label
+// length limits of RFC 1035.
+func checkNameLength(field, name string) error {
+	trimmed := strings.TrimSuffix(name, ".")
+	if trimmed == "" {
+		return nil
+	}
+	if len(trimmed) > MaxNameLength {
+		return errors.Errorf(errNameTooLong, field, name, len(trimmed), MaxNameLength)
+	}
+	for _, label := range strings.Split(trimmed, ".") {
+		if label == "" {
+			return errors.Errorf(errEmptyLabel, field, name)
+		}
+		if len(label) > MaxLabelLength {
+			return errors.Errorf(errLabelTooLong, label, field, name, len(label), MaxLabelLength)
+		}
+	}
+	return nil
+}
+
+// ValidateDomainName checks that the given domain name, optionally fully
+// qualified, consists of RFC 1035 labels and fits in MaxNameLength. Unlike
+// ValidateRecord it also checks the characters of the labels, so it is meant
+// for admission, where a record can still be fixed. Underscores are allowed,
+// as used by SRV and TXT records, and a leading wildcard label if wildcards
+// are allowed.
+func ValidateDomainName(name string, allowWildcard bool) error {
+	trimmed := strings.TrimSuffix(name, ".")
+	if l := len(trimmed); l > MaxNameLength {
+		return errors.Errorf(errNameLength, name, l, MaxNameLength)
+	}
+	for i, label := range strings.Split(trimmed, ".") {
+		if label == wildcard {
+			if !allowWildcard || i != 0 {
+				return errors.New(errWildcard)
+			}
+			continue
+		}
+		if err := ValidateLabel(label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateLabel checks that the given label is 1 to 63 letters, digits,
+// hyphens or underscores, and does not start or end with a hyphen.
+func ValidateLabel(label string) error {
+	if len(label) == 0 || len(label) > MaxLabelLength {
+		return errors.Errorf(errLabelLength, label, len(label), MaxLabelLength)
+	}
+	for _, r := range label {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return errors.Errorf(errInvalidCharacter, label, r)
+		}
+	}
+	if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return errors.Errorf(errHyphen, label)
+	}
+	return nil
+}
+
+// ValidateTTL checks that the given TTL, a number as in the Terraform
+// arguments, is within the range RFC 2181 allows.
+func ValidateTTL(ttl float64) error {
+	if ttl < 0 || ttl > MaxTTL {
+		return errors.Errorf(errTTLRange, MaxTTL)
+	}
+	return nil
+}
+
+// validateTXT checks that the given TXT value fits in a single record once it
+// is split into character-strings, each prefixed with a length octet.
+func validateTXT(txt string) error {
+	chunks := (len(txt) + maxCharacterStringLength - 1) / maxCharacterStringLength
+	if l := len(txt) + chunks; l > maxRDataLength {
+		return errors.Errorf(errTXTTooLong, len(txt), maxRDataLength)
+	}
+	return nil
+}
+
+// stringValues returns the strings held by a Terraform argument that is either
+// a single string or a list of strings.
+func stringValues(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		values := make([]string, 0, len(t))
+		for _, e := range t {
+			if s, ok := e.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestValidateRecord(t *testing.T) {
+	label63 := strings.Repeat("a", MaxLabelLength)
+	label64 := strings.Repeat("a", MaxLabelLength+1)
+	// name253 is a name of exactly MaxNameLength characters: three labels of
+	// 63 characters and one of 61, joined by dots.
+	name253 := strings.Join([]string{label63, label63, label63, strings.Repeat("b", 61)}, ".")
+
+	cases := map[string]struct {
+		reason     string
+		parameters map[string]any
+		want       error
+	}{
+		"Valid": {
+			reason:     "A record within the limits should be valid.",
+			parameters: map[string]any{keyZone: "example.com.", keyName: "www", "addresses": []any{"192.0.2.1"}},
+		},
+		"Apex": {
+			reason:     "A record at the apex should be validated as the zone.",
+			parameters: map[string]any{keyZone: "example.com.", keyName: zoneApex},
+		},
+		"MaxNameLength": {
+			reason:     "A name of exactly 253 characters should be valid.",
+			parameters: map[string]any{keyZone: name253 + "."},
+		},
+		"NameTooLong": {
+			reason:     "A name longer than 253 characters should be rejected.",
+			parameters: map[string]any{keyZone: name253 + ".", keyName: "x"},
+			want:       errors.Errorf(errNameTooLong, keyName, "x."+name253+".", MaxNameLength+2, MaxNameLength),
+		},
+		"MaxLabelLength": {
+			reason:     "A label of exactly 63 characters should be valid.",
+			parameters: map[string]any{keyZone: "example.com.", keyName: label63},
+		},
+		"LabelTooLong": {
+			reason:     "A label longer than 63 characters should be rejected.",
+			parameters: map[string]any{keyZone: "example.com.", keyName: label64},
+			want:       errors.Errorf(errLabelTooLong, label64, keyName, label64+".example.com.", MaxLabelLength+1, MaxLabelLength),
+		},
+		"EmptyLabel": {
+			reason:     "A name with an empty label should be rejected.",
+			parameters: map[string]any{keyZone: "example.com.", keyName: "a..b"},
+			want:       errors.Errorf(errEmptyLabel, keyName, "a..b.example.com."),
+		},
+		"TargetLabelTooLong": {
+			reason:     "Domain names in nested blocks, such as an MX exchange, should be validated.",
+			parameters: map[string]any{keyZone: "example.com.", "mx": []any{map[string]any{"exchange": label64 + ".example.com."}}},
+			want:       errors.Errorf(errLabelTooLong, label64, "exchange", label64+".example.com.", MaxLabelLength+1, MaxLabelLength),
+		},
+		"MaxTXT": {
+			reason:     "A TXT value that fits in the record data once split should be valid.",
+			parameters: map[string]any{keyZone: "example.com.", keyTXT: []any{strings.Repeat("t", maxRDataLength-257)}},
+		},
+		"TXTTooLong": {
+			reason:     "A TXT value exceeding the record data once split should be rejected.",
+			parameters: map[string]any{keyZone: "example.com.", keyTXT: []any{strings.Repeat("t", maxRDataLength)}},
+			want:       errors.Errorf(errTXTTooLong, maxRDataLength, maxRDataLength),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateRecord(tc.parameters)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateRecord(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateDomainName(t *testing.T) {
+	label63 := strings.Repeat("a", MaxLabelLength)
+	label64 := strings.Repeat("a", MaxLabelLength+1)
+	name254 := strings.Join([]string{label63, label63, label63, strings.Repeat("b", 62)}, ".")
+
+	cases := map[string]struct {
+		reason        string
+		name          string
+		allowWildcard bool
+		want          error
+	}{
+		"Valid": {
+			reason: "A name of valid labels should be allowed.",
+			name:   "www.example.com",
+		},
+		"FQDN": {
+			reason: "A fully qualified name should be allowed.",
+			name:   "www.example.com.",
+		},
+		"Underscore": {
+			reason: "Underscores, as used by SRV and TXT records, should be allowed.",
+			name:   "_sip._tcp.example.com.",
+		},
+		"Wildcard": {
+			reason:        "A leftmost wildcard should be allowed where wildcards are.",
+			name:          "*.example.com.",
+			allowWildcard: true,
+		},
+		"WildcardNotAllowed": {
+			reason: "A wildcard should be rejected where wildcards are not allowed, such as in a zone.",
+			name:   "*.example.com.",
+			want:   errors.New(errWildcard),
+		},
+		"WildcardNotLeftmost": {
+			reason:        "A wildcard that is not the leftmost label should be rejected.",
+			name:          "dev.*.example.com.",
+			allowWildcard: true,
+			want:          errors.New(errWildcard),
+		},
+		"InvalidCharacter": {
+			reason: "A label with other characters than letters, digits, hyphens and underscores should be rejected.",
+			name:   "w w.example.com.",
+			want:   errors.Errorf(errInvalidCharacter, "w w", ' '),
+		},
+		"LeadingHyphen": {
+			reason: "A label starting with a hyphen should be rejected.",
+			name:   "-www.example.com.",
+			want:   errors.Errorf(errHyphen, "-www"),
+		},
+		"TrailingHyphen": {
+			reason: "A label ending with a hyphen should be rejected.",
+			name:   "www-.example.com.",
+			want:   errors.Errorf(errHyphen, "www-"),
+		},
+		"EmptyLabel": {
+			reason: "An empty label should be rejected.",
+			name:   "a..example.com.",
+			want:   errors.Errorf(errLabelLength, "", 0, MaxLabelLength),
+		},
+		"LabelTooLong": {
+			reason: "A label longer than 63 characters should be rejected.",
+			name:   label64 + ".example.com.",
+			want:   errors.Errorf(errLabelLength, label64, MaxLabelLength+1, MaxLabelLength),
+		},
+		"NameTooLong": {
+			reason: "A name longer than 253 characters should be rejected.",
+			name:   name254 + ".",
+			want:   errors.Errorf(errNameLength, name254+".", MaxNameLength+1, MaxNameLength),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateDomainName(tc.name, tc.allowWildcard)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateDomainName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateTTL(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		ttl    float64
+		want   error
+	}{
+		"Zero": {
+			reason: "A zero TTL should be allowed.",
+		},
+		"Max": {
+			reason: "The largest TTL RFC 2181 allows should be allowed.",
+			ttl:    MaxTTL,
+		},
+		"Negative": {
+			reason: "A negative TTL should be rejected.",
+			ttl:    -1,
+			want:   errors.Errorf(errTTLRange, MaxTTL),
+		},
+		"TooLarge": {
+			reason: "A TTL larger than 2^31-1 should be rejected.",
+			ttl:    MaxTTL + 1,
+			want:   errors.Errorf(errTTLRange, MaxTTL),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateTTL(tc.ttl)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateTTL(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package clients
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+// credentialsCache caches the credentials unmarshalled from the Secret of a
+// ProviderConfig, so that the managed resources sharing a ProviderConfig do
+// not extract and parse its Secret on every reconcile. Entries are keyed by
+// the generation of the ProviderConfig, which changes with the selectors and
+// connection settings the credentials are derived from, and by the
+// resourceVersion of the Secret, which changes with its data.
+//
+// Only the credentials as read from the Secret are cached. File references,
+// TSIG keys, overrides, profiles and keytabs are still resolved on every
+// setup, as they depend on the managed resource or on files that change
+// without any resourceVersion. For the same reason the credentials of the
+// Filesystem and Environment sources and of a secretClusterRef, whose Secret
+// lives in a cluster the provider does not watch, are never cached.
+type credentialsCache struct {
+	mu sync.Mutex
+	// entries holds the latest credentials of every ProviderConfig, so that
+	// an entry is replaced rather than added when either version changes.
+	entries map[types.UID]credentialsCacheEntry
+}
+
+type credentialsCacheKey struct {
+	uid           types.UID
+	generation    int64
+	secretVersion string
+}
+
+type credentialsCacheEntry struct {
+	key   credentialsCacheKey
+	creds map[string]string
+}
+
+func newCredentialsCache() *credentialsCache {
+	return &credentialsCache{entries: map[types.UID]credentialsCacheEntry{}}
+}
+
+// Key returns the cache key of the credentials of the given ProviderConfig,
+// and false when its credentials must not be cached or the version of its
+// Secret cannot be read, in which case the extraction reports the error.
+func (c *credentialsCache) Key(ctx context.Context, kube client.Client, pc client.Object, pcSpec *namespacedv1beta1.ProviderConfigSpec) (credentialsCacheKey, bool) {
+	creds := pcSpec.Credentials
+	if c == nil || pc == nil || pc.GetUID() == "" || creds.Source != xpv1.CredentialsSourceSecret || creds.SecretRef == nil || creds.SecretClusterRef != nil {
+		return credentialsCacheKey{}, false
+	}
+	s := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: creds.SecretRef.Namespace, Name: creds.SecretRef.Name}, s); err != nil {
+		return credentialsCacheKey{}, false
+	}
+	return credentialsCacheKey{uid: pc.GetUID(), generation: pc.GetGeneration(), secretVersion: s.GetResourceVersion()}, true
+}
+
+// Get returns a copy of the credentials cached for the given key.
+func (c *credentialsCache) Get(key credentialsCacheKey) (map[string]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key.uid]
+	if !ok || e.key != key {
+		return nil, false
+	}
+	return maps.Clone(e.creds), true
+}
+
+// Set caches a copy of the given credentials for the given key.
+func (c *credentialsCache) Set(key credentialsCacheKey, creds map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key.uid] = credentialsCacheEntry{key: key, creds: maps.Clone(creds)}
+}
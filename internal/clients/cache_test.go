@@ -0,0 +1,160 @@
+package clients
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+const cacheTestCredentials = `{"rfc":"2845","server":"ns1.example.com","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"c2VjcmV0"}`
+
+func TestCredentialsCacheKey(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		mod    func(*namespacedv1beta1.ProviderConfigSpec)
+		secret bool
+		want   bool
+	}{
+		"Secret": {
+			reason: "Credentials of an existing local Secret should be cached.",
+			secret: true,
+			want:   true,
+		},
+		"MissingSecret": {
+			reason: "Credentials should not be cached when the Secret cannot be read, so that the extraction reports the error.",
+		},
+		"SecretClusterRef": {
+			reason: "Credentials of a Secret in a remote cluster should not be cached, as its resourceVersion is not watched.",
+			mod: func(s *namespacedv1beta1.ProviderConfigSpec) {
+				s.Credentials.SecretClusterRef = &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "kubeconfig"}, Key: "kubeconfig"}
+			},
+			secret: true,
+		},
+		"Filesystem": {
+			reason: "Credentials of a file should not be cached, as files have no resourceVersion.",
+			mod: func(s *namespacedv1beta1.ProviderConfigSpec) {
+				s.Credentials.Source = xpv1.CredentialsSourceFilesystem
+				s.Credentials.Fs = &xpv1.FsSelector{Path: "/etc/dns/credentials"}
+			},
+			secret: true,
+		},
+		"Environment": {
+			reason: "Credentials of an environment variable should not be cached.",
+			mod: func(s *namespacedv1beta1.ProviderConfigSpec) {
+				s.Credentials.Source = xpv1.CredentialsSourceEnvironment
+				s.Credentials.Env = &xpv1.EnvSelector{Name: "DNS_CREDENTIALS"}
+			},
+			secret: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := testProviderConfigWith(tc.mod)
+			pcSpec, err := ProviderConfigSpec(pc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := testClient(t)
+			if tc.secret {
+				c = testClient(t, testSecret(cacheTestCredentials))
+			}
+			_, got := newCredentialsCache().Key(context.Background(), c, pc, pcSpec)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nKey(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCredentialsCacheGet(t *testing.T) {
+	key := credentialsCacheKey{uid: testProviderConfig, generation: 1, secretVersion: "1"}
+	c := newCredentialsCache()
+	c.Set(key, map[string]string{keyServer: "ns1.example.com"})
+
+	got, ok := c.Get(key)
+	if !ok {
+		t.Fatal("Get(...): want a cached entry")
+	}
+	got[keyServer] = "modified"
+	if again, _ := c.Get(key); again[keyServer] != "ns1.example.com" {
+		t.Errorf("Get(...): modifying the returned credentials must not modify the cache, got %q", again[keyServer])
+	}
+
+	for name, k := range map[string]credentialsCacheKey{
+		"Generation":    {uid: key.uid, generation: 2, secretVersion: key.secretVersion},
+		"SecretVersion": {uid: key.uid, generation: key.generation, secretVersion: "2"},
+		"UID":           {uid: "other", generation: key.generation, secretVersion: key.secretVersion},
+	} {
+		if _, ok := c.Get(k); ok {
+			t.Errorf("Get(...): a different %s should miss the cache", name)
+		}
+	}
+
+	c.Set(credentialsCacheKey{uid: key.uid, generation: 2, secretVersion: "1"}, map[string]string{})
+	if _, ok := c.Get(key); ok {
+		t.Errorf("Set(...): a new generation should replace the cached entry")
+	}
+	if len(c.entries) != 1 {
+		t.Errorf("Set(...): want one entry per ProviderConfig, got %d", len(c.entries))
+	}
+}
+
+func TestTerraformSetupCachesCredentials(t *testing.T) {
+	ctx := context.Background()
+	secret := testSecret(cacheTestCredentials)
+	pc := testProviderConfigWith(nil)
+	c := testClient(t, pc, secret)
+
+	extractions := 0
+	setup := TerraformSetupBuilder("1.5.7", "hashicorp/dns", "3.4.0", WithCredentialExtractor(countingExtractor(&extractions)))
+	run := func() terraform.Setup {
+		t.Helper()
+		ps, err := setup(ctx, c, testRecordSet())
+		if err != nil {
+			t.Fatalf("setup(...): %v", err)
+		}
+		return ps
+	}
+
+	first := run()
+	second := run()
+	if extractions != 1 {
+		t.Errorf("setup(...): want the credentials extracted once for an unchanged Secret, got %d extractions", extractions)
+	}
+	if diff := cmp.Diff(first.Configuration, second.Configuration); diff != "" {
+		t.Errorf("setup(...): a cached setup should build the same configuration: -want, +got:\n%s", diff)
+	}
+
+	secret.Data[testSecretKey] = []byte(`{"rfc":"2845","server":"ns2.example.com","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"c2VjcmV0"}`)
+	if err := c.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	third := run()
+	if extractions != 2 {
+		t.Errorf("setup(...): want the credentials extracted again after the Secret changed, got %d extractions", extractions)
+	}
+	auth := third.Configuration[update].([]any)[0].(map[string]any)
+	if diff := cmp.Diff("ns2.example.com", auth[keyServer]); diff != "" {
+		t.Errorf("setup(...): want the server of the changed Secret: -want, +got:\n%s", diff)
+	}
+
+	if err := c.Get(ctx, client.ObjectKeyFromObject(pc), pc); err != nil {
+		t.Fatal(err)
+	}
+	pc.Generation++
+	pc.Spec.Description = "changed"
+	if err := c.Update(ctx, pc); err != nil {
+		t.Fatal(err)
+	}
+	run()
+	if extractions != 3 {
+		t.Errorf("setup(...): want the credentials extracted again after the ProviderConfig changed, got %d extractions", extractions)
+	}
+}
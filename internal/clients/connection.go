@@ -0,0 +1,53 @@
+package clients
+
+import (
+	"encoding/json"
+	"strconv"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+// applyConnectionSettings adds the connection settings of the given
+// ProviderConfig spec to the credentials. Settings the credentials already
+// hold are kept, so the credentials take precedence.
+func applyConnectionSettings(pcSpec *namespacedv1beta1.ProviderConfigSpec, creds map[string]string) {
+	c := pcSpec.Connection
+	if c == nil {
+		return
+	}
+
+	settings := map[string]string{
+		keyServer:    c.Server,
+		keyRFC:       c.RFC,
+		keyTransport: c.Transport,
+		keyTimeout:   c.Timeout,
+	}
+	if c.Port != nil {
+		settings[keyPort] = strconv.Itoa(*c.Port)
+	}
+	if c.Retries != nil {
+		settings[keyRetries] = strconv.Itoa(*c.Retries)
+	}
+
+	for key, value := range settings {
+		if _, ok := creds[key]; ok || value == "" {
+			continue
+		}
+		creds[key] = value
+	}
+}
+
+// unmarshalCredentials unmarshals the given credentials JSON and adds the
+// connection settings of the given ProviderConfig spec. Empty credentials,
+// such as those of the None source, are allowed when every setting is part
+// of the ProviderConfig spec.
+func unmarshalCredentials(pcSpec *namespacedv1beta1.ProviderConfigSpec, data []byte) (map[string]string, error) {
+	creds := map[string]string{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &creds); err != nil {
+			return nil, unmarshalCredentialsError(err)
+		}
+	}
+	applyConnectionSettings(pcSpec, creds)
+	return creds, nil
+}
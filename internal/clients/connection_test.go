@@ -0,0 +1,93 @@
+package clients
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+func TestUnmarshalCredentials(t *testing.T) {
+	inline := &namespacedv1beta1.ConnectionSettings{
+		Server:    "ns1.example.com",
+		Port:      to(5353),
+		RFC:       "2845",
+		Transport: "tcp",
+		Retries:   to(2),
+		Timeout:   "5s",
+	}
+
+	type want struct {
+		creds map[string]string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason     string
+		connection *namespacedv1beta1.ConnectionSettings
+		data       string
+		want       want
+	}{
+		"InlineOnly": {
+			reason:     "Empty credentials should hold every connection setting of the ProviderConfig.",
+			connection: inline,
+			want: want{creds: map[string]string{
+				keyServer: "ns1.example.com", keyPort: "5353", keyRFC: "2845", keyTransport: "tcp", keyRetries: "2", keyTimeout: "5s",
+			}},
+		},
+		"SecretOnly": {
+			reason: "Credentials should be used as they are when the ProviderConfig has no connection settings.",
+			data:   `{"server":"ns1.example.com","rfc":"2845","key_secret":"c2VjcmV0"}`,
+			want:   want{creds: map[string]string{keyServer: "ns1.example.com", keyRFC: "2845", transactionKeySecret: "c2VjcmV0"}},
+		},
+		"Mixed": {
+			reason:     "Connection settings should be added to the credentials, which take precedence on conflict.",
+			connection: inline,
+			data:       `{"server":"ns2.example.com","retries":"0","key_secret":"c2VjcmV0"}`,
+			want: want{creds: map[string]string{
+				keyServer: "ns2.example.com", keyPort: "5353", keyRFC: "2845", keyTransport: "tcp", keyRetries: "0", keyTimeout: "5s", transactionKeySecret: "c2VjcmV0",
+			}},
+		},
+		"EmptySettings": {
+			reason:     "Unset connection settings should not add empty credentials.",
+			connection: &namespacedv1beta1.ConnectionSettings{Server: "ns1.example.com"},
+			data:       `{"rfc":"2845"}`,
+			want:       want{creds: map[string]string{keyServer: "ns1.example.com", keyRFC: "2845"}},
+		},
+		"EmptyCredentialValue": {
+			reason:     "A credential set to an empty value should still take precedence over a connection setting.",
+			connection: inline,
+			data:       `{"timeout":""}`,
+			want: want{creds: map[string]string{
+				keyServer: "ns1.example.com", keyPort: "5353", keyRFC: "2845", keyTransport: "tcp", keyRetries: "2", keyTimeout: "",
+			}},
+		},
+		"InvalidJSON": {
+			reason:     "Credentials that are not JSON should fail even with connection settings.",
+			connection: inline,
+			data:       `server=ns1.example.com`,
+			want:       want{err: unmarshalCredentialsError(jsonError(`server=ns1.example.com`))},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pcSpec := &namespacedv1beta1.ProviderConfigSpec{Connection: tc.connection}
+			got, err := unmarshalCredentials(pcSpec, []byte(tc.data))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nunmarshalCredentials(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, got); diff != "" {
+				t.Errorf("\n%s\nunmarshalCredentials(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// jsonError returns the error of unmarshalling the given data as JSON credentials.
+func jsonError(data string) error {
+	return json.Unmarshal([]byte(data), &map[string]string{})
+}
@@ -2,26 +2,49 @@ package clients
 
 import (
 	"context"
-	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
 	"github.com/crossplane/upjet/v2/pkg/terraform"
+	"github.com/go-logr/logr"
 	"github.com/hashicorp/terraform-provider-dns/xpprovider"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	clusterv1beta1 "github.com/dana-team/provider-dns-v2/apis/cluster/v1beta1"
 	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+	"github.com/dana-team/provider-dns-v2/config"
 )
 
 const (
 	// error messages
-	errNoProviderConfig     = "no providerConfigRef provided"
-	errGetProviderConfig    = "cannot get referenced ProviderConfig"
-	errTrackUsage           = "cannot track ProviderConfig usage"
-	errExtractCredentials   = "cannot extract credentials"
-	errUnmarshalCredentials = "cannot unmarshal dns-v2 credentials as JSON"
+	errNoProviderConfig                  = "no providerConfigRef provided"
+	errGetProviderConfig                 = "cannot get referenced ProviderConfig"
+	errUnknownProviderConfigKind         = "providerConfigRef kind %q is not supported, valid kinds are %s and %s"
+	errTrackUsage                        = "cannot track ProviderConfig usage"
+	errExtractCredentials                = "cannot extract credentials"
+	errClusterConfigNeedsSecretNamespace = "a ClusterProviderConfig must set the namespace of its secret references"
+	errUnmarshalCredentials              = "cannot unmarshal dns-v2 credentials as JSON"
+	errBuildAuthConfig                   = "cannot build dns-v2 provider configuration"
+	errMissingServer                     = "server is required in dns-v2 credentials"
+	errDuplicateConfigKey                = "provider configuration key %q is set more than once"
+	errInvalidKeyName                    = "key_name %q is not a valid domain name"
+	errInvalidPort                       = "%s must be an integer between 1 and 65535, got %q"
+	errInvalidRetries                    = "retries must be a non-negative integer, got %q"
+	errInvalidTimeout                    = "timeout must be a non-negative duration such as 30s or a number of seconds, got %q"
+	errInvalidKeyAlgorithm               = "unsupported key_algorithm %q, valid values are %s"
+	errInvalidTransport                  = "unsupported transport %q, valid values are %s"
+	errInvalidRFC                        = "unsupported rfc %q, valid values are %s, %s or %s for unauthenticated updates"
+	errMissingGSSTSIGKeys                = "GSS-TSIG (rfc 3645) credentials are missing required keys: %s"
+	errGSSTSIGAuthMethod                 = "GSS-TSIG (rfc 3645) credentials must set exactly one of %s or %s"
+	errUsernameRealmConflict             = "username %q includes realm %q, which conflicts with the configured realm %q"
 
 	// general parameters
 	keyRFC       = "rfc"
@@ -31,6 +54,27 @@ const (
 	keyRetries   = "retries"
 	keyTimeout   = "timeout"
 	keyTransport = "transport"
+	keyTCPPort   = "tcp_port"
+	keyUDPPort   = "udp_port"
+
+	// unauthenticatedRFC selects unauthenticated RFC 2136 updates, like an
+	// absent rfc.
+	unauthenticatedRFC = "none"
+
+	// provider defaults
+	defaultTransport = "udp"
+	defaultPort      = 53
+	defaultRetries   = 3
+	defaultTimeout   = "0"
+	tcpTransport     = "tcp"
+	maxPort          = 65535
+
+	// defaultMaxRetries is used when the ProviderConfig does not set
+	// maxRetries.
+	defaultMaxRetries = 10
+
+	// maxKeyNameLabelLength is the maximum length of a label of a TSIG key name.
+	maxKeyNameLabelLength = 63
 
 	// gss-tsig (RFC 3645) parameters
 	gsstsigRFC  = "3645"
@@ -47,14 +91,113 @@ const (
 	transactionKeySecret    = "key_secret"
 )
 
+const (
+	// AnnotationKeyAlgorithm overrides the key_algorithm of the ProviderConfig
+	// credentials for the annotated managed resource.
+	AnnotationKeyAlgorithm = "dns-v2.crossplane.io/key-algorithm"
+)
+
+// transports are the transports supported by the provider.
+var transports = []string{"udp", "udp4", "udp6", "tcp", "tcp4", "tcp6"}
+
+// keyAlgorithms are the TSIG HMAC algorithms supported by the provider.
+var keyAlgorithms = []string{"hmac-md5", "hmac-sha1", "hmac-sha256", "hmac-sha512"}
+
+// A SetupOption configures the terraform.SetupFn built by TerraformSetupBuilder.
+type SetupOption func(*setupOptions)
+
+type setupOptions struct {
+	usageDebounce time.Duration
+	exportConfig  bool
+	logger        logr.Logger
+	metrics       *SetupMetrics
+	extract       CredentialExtractor
+	trailingDot   config.TrailingDotPolicy
+}
+
+// WithUsageDebounce coalesces the ProviderConfigUsage writes of a managed
+// resource, tracking its usage at most once within the given period. A zero
+// period tracks the usage on every reconcile.
+func WithUsageDebounce(period time.Duration) SetupOption {
+	return func(o *setupOptions) {
+		o.usageDebounce = period
+	}
+}
+
+// WithConfigExport logs the effective Terraform provider configuration of
+// every setup as a redacted terraform-provider-dns provider block.
+func WithConfigExport() SetupOption {
+	return func(o *setupOptions) {
+		o.exportConfig = true
+	}
+}
+
+// WithLogger logs the setup of every managed resource to the given logger
+// instead of the logger of the reconcile context. Details of how the
+// credentials are built, with secrets redacted, are logged at V(1).
+func WithLogger(l logr.Logger) SetupOption {
+	return func(o *setupOptions) {
+		o.logger = l
+	}
+}
+
+// WithMetrics records the outcome and duration of every setup in the given
+// metrics.
+func WithMetrics(m *SetupMetrics) SetupOption {
+	return func(o *setupOptions) {
+		o.metrics = m
+	}
+}
+
+// WithTrailingDotPolicy canonicalizes the domain names the Terraform Plugin
+// Framework resources read back from the DNS server according to the given
+// policy, which should be the policy the provider configuration applies to
+// their arguments.
+func WithTrailingDotPolicy(p config.TrailingDotPolicy) SetupOption {
+	return func(o *setupOptions) {
+		o.trailingDot = p
+	}
+}
+
+// WithCredentialExtractor extracts the credentials of ProviderConfigs with the
+// given extractor instead of resource.CommonCredentialExtractor, for example to
+// build setups from canned credentials without an API server.
+func WithCredentialExtractor(e CredentialExtractor) SetupOption {
+	return func(o *setupOptions) {
+		o.extract = e
+	}
+}
+
 // TerraformSetupBuilder builds Terraform a terraform.SetupFn function which
 // returns Terraform provider setup configuration.
 //
 // This function is called once during provider initialization to create a SetupFn.
 // The returned SetupFn is then called by Upjet for each managed resource reconciliation.
-func TerraformSetupBuilder(version, providerSource, providerVersion string) terraform.SetupFn {
-	return func(ctx context.Context, client client.Client, mg resource.Managed) (terraform.Setup, error) {
-		ps := terraform.Setup{
+func TerraformSetupBuilder(version, providerSource, providerVersion string, opts ...SetupOption) terraform.SetupFn {
+	o := &setupOptions{extract: resource.CommonCredentialExtractor}
+	for _, opt := range opts {
+		opt(o)
+	}
+	usage := newUsageDebouncer(o.usageDebounce)
+	servers := newServerSelector(serverSelectionTTL)
+	cache := newCredentialsCache()
+
+	return func(ctx context.Context, client client.Client, mg resource.Managed) (ps terraform.Setup, err error) {
+		ctx, span := tracer.Start(ctx, "TerraformSetup")
+		start := time.Now()
+		outcome := outcomeProviderConfigFailure
+		defer func() {
+			if err == nil {
+				outcome = outcomeSuccess
+			} else {
+				span.SetStatus(codes.Error, outcome)
+			}
+			span.SetAttributes(attrOutcome.String(outcome))
+			span.End()
+			o.metrics.observe(outcome, time.Since(start))
+		}()
+
+		ps = terraform.Setup{
 			Version: version,
 			Requirement: terraform.ProviderRequirement{
 				Source:  providerSource,
@@ -62,155 +205,379 @@ func TerraformSetupBuilder(version, providerSource, providerVersion string) terr
 			},
 		}
 
-		pcSpec, err := resolveProviderConfig(ctx, client, mg)
+		pc, pcSpec, err := resolveProviderConfig(ctx, client, mg, usage)
 		if err != nil {
 			return terraform.Setup{}, errors.Wrap(err, "cannot resolve provider config")
 		}
 
-		data, err := resource.CommonCredentialExtractor(ctx, pcSpec.Credentials.Source, client, pcSpec.Credentials.CommonCredentialSelectors)
+		logger := log.FromContext(ctx)
+		if o.logger.GetSink() != nil {
+			logger = o.logger.WithValues("kind", mg.GetObjectKind().GroupVersionKind().Kind, "name", mg.GetName(), "namespace", mg.GetNamespace())
+		}
+		logger = logger.WithValues("description", pcSpec.Description)
+		logger.V(1).Info("Resolved ProviderConfig", "source", pcSpec.Credentials.Source)
+
+		outcome = outcomeResourceFailure
+		params, err := parameters(mg)
+		if err != nil {
+			return ps, err
+		}
+
+		// Records are only checked when they may be created or updated, so
+		// that records failing the checks can still be observed and deleted.
+		if sendsUpdates(mg) {
+			if err := config.ValidateRecord(params); err != nil {
+				return ps, err
+			}
+			if err := checkRecordSetSize(pcSpec, params); err != nil {
+				return ps, err
+			}
+		}
+
+		key, cacheable := cache.Key(ctx, client, pc, pcSpec)
+		creds, cached := cache.Get(key)
+		if !cached {
+			outcome = outcomeCredentialExtractFailure
+			extractCtx, extractSpan := tracer.Start(ctx, "ExtractCredentials")
+			data, err := extractCredentials(extractCtx, client, o.extract, pcSpec)
+			extractSpan.End()
+			if err != nil {
+				return ps, err
+			}
+
+			outcome = outcomeUnmarshalFailure
+			creds, err = unmarshalCredentials(pcSpec, data)
+			if err != nil {
+				return ps, err
+			}
+			if cacheable {
+				cache.Set(key, creds)
+			}
+		}
+		for _, key := range unknownCredentialKeys(creds) {
+			logger.Info("Ignoring unknown credentials key, check it for typos", "key", key)
+		}
+
+		outcome = outcomeCredentialFailure
+		if err := resolveFileReferences(creds); err != nil {
+			return ps, err
+		}
+
+		if err := applyTSIGKey(ctx, client, o.extract, pcSpec, mg, creds); err != nil {
+			return ps, err
+		}
+
+		if err := applyResourceOverrides(mg, creds); err != nil {
+			return ps, err
+		}
+
+		zone, _ := params[paramZone].(string)
+		profile, err := selectProfile(pcSpec, zone)
 		if err != nil {
-			return ps, errors.Wrap(err, errExtractCredentials)
+			return ps, err
+		}
+		if profile != nil {
+			logger.V(1).Info("Using zone profile", "profile", profile.Name)
+			applyProfile(profile, creds)
+		}
+
+		if retries := creds[keyRetries]; clampRetries(pcSpec, creds) {
+			logger.Info("Lowered retries to the maxRetries of the ProviderConfig", "retries", retries, "maxRetries", creds[keyRetries])
+		}
+
+		if applyTCPFallback(pcSpec, params, creds) {
+			logger.V(1).Info("Using TCP for an update exceeding the tcpFallbackThreshold", "transport", creds[keyTransport])
 		}
 
-		creds := map[string]string{}
-		if err := json.Unmarshal(data, &creds); err != nil {
-			return ps, errors.Wrap(err, errUnmarshalCredentials)
+		// Servers are probed with the port and transport of the update.
+		if err := servers.Select(ctx, creds, zone); err != nil {
+			return ps, err
+		}
+
+		if creds[keyRFC] == gsstsigRFC {
+			if err := applyKrb5Config(creds); err != nil {
+				return ps, err
+			}
+			if realm := creds[keyRealm]; normalizeRealm(creds) {
+				logger.V(1).Info("Normalized the Kerberos realm to upper case", "from", realm, "to", creds[keyRealm])
+			}
+			if err := loadSecretKeytab(ctx, client, o.extract, pcSpec, creds); err != nil {
+				return ps, err
+			}
+			if err := materializeKeytab(creds); err != nil {
+				return ps, redactError(err, creds)
+			}
+			if err := checkKeytabPrincipal(creds); err != nil {
+				if creds[keyKeytabPrincipalCheck] == keytabPrincipalCheckStrict {
+					return ps, redactError(err, creds)
+				}
+				logger.Info("Keytab principal does not match the configured credentials", "reason", redactError(err, creds).Error())
+			}
 		}
 
 		ps.Configuration = map[string]any{}
 
-		authConfig := buildAuthConfig(creds)
+		outcome = outcomeAuthValidationFailure
+		if creds[keyServer] == "" {
+			outcome = outcomeMissingServer
+		}
+		_, buildSpan := tracer.Start(ctx, "BuildAuthConfig", trace.WithAttributes(attrAuthMode.String(authMode(creds[keyRFC]))))
+		authConfig, err := buildAuthConfig(creds, pcSpec.ExplicitDefaults, logger)
+		buildSpan.End()
+		if err != nil {
+			return ps, redactError(errors.Wrap(err, errBuildAuthConfig), creds)
+		}
 
 		ps.Configuration[update] = []any{authConfig}
 
+		if o.exportConfig {
+			logger.Info("Effective provider configuration", "config", ExportProviderConfig(ps.Configuration))
+		}
+
+		outcome = outcomeProviderFailure
 		fwProvider, _ := xpprovider.GetProvider(ctx)
 
 		if fwProvider == nil {
 			return ps, errors.New("framework provider is nil")
 		}
 
-		ps.FrameworkProvider = fwProvider
+		ps.FrameworkProvider = config.CanonicalizeObserved(fwProvider, o.trailingDot)
 
 		return ps, nil
 	}
 }
 
 // resolveProviderConfig determines which ProviderConfig to use based on the resource type
-// and extracts its spec. Handles both legacy (cluster-scoped) and modern (namespace-scoped) resources.
-func resolveProviderConfig(ctx context.Context, crClient client.Client, mg resource.Managed) (*namespacedv1beta1.ProviderConfigSpec, error) {
+// and returns it with its spec. Handles both legacy (cluster-scoped) and modern (namespace-scoped) resources.
+func resolveProviderConfig(ctx context.Context, crClient client.Client, mg resource.Managed, usage *usageDebouncer) (client.Object, *namespacedv1beta1.ProviderConfigSpec, error) {
+	ctx, span := tracer.Start(ctx, "ResolveProviderConfig")
+	defer span.End()
+
 	switch managed := mg.(type) {
 	case resource.LegacyManaged:
-		return resolveLegacy(ctx, crClient, managed)
+		span.SetAttributes(attrScope.String(scopeLegacy))
+		return resolveLegacy(ctx, crClient, managed, usage)
 	case resource.ModernManaged:
-		return resolveModern(ctx, crClient, managed)
+		span.SetAttributes(attrScope.String(scopeModern))
+		return resolveModern(ctx, crClient, managed, usage)
 	default:
-		return nil, errors.New("resource is not a managed resource")
+		return nil, nil, errors.New("resource is not a managed resource")
 	}
 }
 
 // resolveLegacy handles legacy cluster-scoped ProviderConfig resources
-func resolveLegacy(ctx context.Context, client client.Client, mg resource.LegacyManaged) (*namespacedv1beta1.ProviderConfigSpec, error) {
+func resolveLegacy(ctx context.Context, client client.Client, mg resource.LegacyManaged, usage *usageDebouncer) (client.Object, *namespacedv1beta1.ProviderConfigSpec, error) {
 	configRef := mg.GetProviderConfigReference()
 	if configRef == nil {
-		return nil, errors.New(errNoProviderConfig)
+		return nil, nil, errors.New(errNoProviderConfig)
 	}
 	pc := &clusterv1beta1.ProviderConfig{}
 	if err := client.Get(ctx, types.NamespacedName{Name: configRef.Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetProviderConfig)
+		return nil, nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
 	t := resource.NewLegacyProviderConfigUsageTracker(client, &clusterv1beta1.ProviderConfigUsage{})
-	if err := t.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackUsage)
+	track := resource.TrackerFn(func(ctx context.Context, _ resource.Managed) error { return t.Track(ctx, mg) })
+	if err := usage.Track(ctx, mg, clusterv1beta1.ProviderConfigKind, configRef.Name, track); err != nil {
+		return nil, nil, errors.Wrap(err, errTrackUsage)
 	}
 
-	return toSharedPCSpec(pc)
+	return pc, toSharedPCSpec(pc), nil
 }
 
 // resolveModern handles modern namespace-scoped ProviderConfig resources
-func resolveModern(ctx context.Context, crClient client.Client, mg resource.ModernManaged) (*namespacedv1beta1.ProviderConfigSpec, error) {
+func resolveModern(ctx context.Context, crClient client.Client, mg resource.ModernManaged, usage *usageDebouncer) (client.Object, *namespacedv1beta1.ProviderConfigSpec, error) {
 	configRef := mg.GetProviderConfigReference()
 	if configRef == nil {
-		return nil, errors.New(errNoProviderConfig)
+		return nil, nil, errors.New(errNoProviderConfig)
 	}
 
-	pcRuntimeObj, err := crClient.Scheme().New(namespacedv1beta1.SchemeGroupVersion.WithKind(configRef.Kind))
+	kind, err := providerConfigKind(configRef.Kind)
 	if err != nil {
-		return nil, errors.Wrap(err, "unknown GVK for ProviderConfig")
+		return nil, nil, err
+	}
+
+	pcRuntimeObj, err := crClient.Scheme().New(namespacedv1beta1.SchemeGroupVersion.WithKind(kind))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "unknown GVK for ProviderConfig")
 	}
 
 	pcObj, ok := pcRuntimeObj.(client.Object)
 	if !ok {
-		return nil, errors.New("ProviderConfig is not a client.Object")
+		return nil, nil, errors.New("ProviderConfig is not a client.Object")
+	}
+
+	// A ClusterProviderConfig is cluster-scoped, so it is not looked up in
+	// the namespace of the managed resource.
+	key := types.NamespacedName{Name: configRef.Name, Namespace: mg.GetNamespace()}
+	if kind == namespacedv1beta1.ClusterProviderConfigKind {
+		key.Namespace = ""
+	}
+	if err := crClient.Get(ctx, key, pcObj); err != nil {
+		return nil, nil, errors.Wrap(err, errGetProviderConfig)
 	}
 
-	if err := crClient.Get(ctx, types.NamespacedName{Name: configRef.Name, Namespace: mg.GetNamespace()}, pcObj); err != nil {
-		return nil, errors.Wrap(err, errGetProviderConfig)
+	pcSpec, err := ProviderConfigSpec(pcObj)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	var pcSpec namespacedv1beta1.ProviderConfigSpec
 	pcu := &namespacedv1beta1.ProviderConfigUsage{}
+	t := resource.NewProviderConfigUsageTracker(crClient, pcu)
+	track := resource.TrackerFn(func(ctx context.Context, _ resource.Managed) error { return t.Track(ctx, mg) })
+	if err := usage.Track(ctx, mg, kind, configRef.Name, track); err != nil {
+		return nil, nil, errors.Wrap(err, errTrackUsage)
+	}
 
-	switch pc := pcObj.(type) {
-	case *namespacedv1beta1.ProviderConfig:
-		pcSpec = pc.Spec
-		if pcSpec.Credentials.SecretRef != nil {
-			pcSpec.Credentials.SecretRef.Namespace = mg.GetNamespace()
-		}
-	case *namespacedv1beta1.ClusterProviderConfig:
-		pcSpec = pc.Spec
+	return pcObj, pcSpec, nil
+}
+
+// providerConfigKind returns the kind of ProviderConfig a modern managed
+// resource references, which is a ProviderConfig when the kind is not set.
+func providerConfigKind(kind string) (string, error) {
+	switch kind {
+	case "":
+		return namespacedv1beta1.ProviderConfigKind, nil
+	case namespacedv1beta1.ProviderConfigKind, namespacedv1beta1.ClusterProviderConfigKind:
+		return kind, nil
 	default:
-		return nil, errors.New("unknown provider config type")
+		return "", errors.Errorf(errUnknownProviderConfigKind, kind, namespacedv1beta1.ProviderConfigKind, namespacedv1beta1.ClusterProviderConfigKind)
 	}
+}
 
-	t := resource.NewProviderConfigUsageTracker(crClient, pcu)
-	if err := t.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackUsage)
+// applyResourceOverrides applies the credential overrides set through
+// annotations on the given managed resource.
+func applyResourceOverrides(mg resource.Managed, creds map[string]string) error {
+	if algorithm, ok := mg.GetAnnotations()[AnnotationKeyAlgorithm]; ok {
+		if err := validateKeyAlgorithm(strings.TrimSuffix(algorithm, ".")); err != nil {
+			return errors.Wrapf(err, "invalid %s annotation", AnnotationKeyAlgorithm)
+		}
+		creds[transactionKeyAlgorithm] = algorithm
 	}
-
-	return &pcSpec, nil
+	return nil
 }
 
-// toSharedPCSpec converts a cluster-scoped ProviderConfig spec to the shared spec format
-func toSharedPCSpec(pc *clusterv1beta1.ProviderConfig) (*namespacedv1beta1.ProviderConfigSpec, error) {
+// toSharedPCSpec converts a cluster-scoped ProviderConfig spec to the shared spec format.
+// The fields are copied explicitly, and the credentials and profiles are converted
+// between their identical types, so that a field added to only one of those types
+// fails the build instead of being dropped.
+func toSharedPCSpec(pc *clusterv1beta1.ProviderConfig) *namespacedv1beta1.ProviderConfigSpec {
 	if pc == nil {
-		return nil, nil
+		return nil
 	}
 
-	data, err := json.Marshal(pc.Spec)
-	if err != nil {
-		return nil, err
+	spec := pc.Spec.DeepCopy()
+	mSpec := &namespacedv1beta1.ProviderConfigSpec{
+		Description:          spec.Description,
+		MaxRecordSetSize:     spec.MaxRecordSetSize,
+		TCPFallbackThreshold: spec.TCPFallbackThreshold,
+		MaxRetries:           spec.MaxRetries,
+		ExplicitDefaults:     spec.ExplicitDefaults,
+		DefaultProfile:       spec.DefaultProfile,
+		Connection:           (*namespacedv1beta1.ConnectionSettings)(spec.Connection),
+		Credentials:          namespacedv1beta1.ProviderCredentials(spec.Credentials),
 	}
-
-	var mSpec namespacedv1beta1.ProviderConfigSpec
-	err = json.Unmarshal(data, &mSpec)
-	return &mSpec, err
+	for _, p := range spec.Profiles {
+		mSpec.Profiles = append(mSpec.Profiles, namespacedv1beta1.ZoneProfile(p))
+	}
+	for _, k := range spec.TSIGKeys {
+		mSpec.TSIGKeys = append(mSpec.TSIGKeys, namespacedv1beta1.TSIGKey(k))
+	}
+	return mSpec
 }
 
 // buildAuthConfig builds the auth configuration for the DNS provider.
 // This constructs the nested map structure that matches the Terraform DNS provider schema.
-func buildAuthConfig(creds map[string]string) map[string]any {
+// The selected authentication mode, the optional keys set and any normalization
+// of the credentials are logged at V(1), without their secret values.
+func buildAuthConfig(creds map[string]string, explicitDefaults bool, logger logr.Logger) (map[string]any, error) {
 	config := map[string]any{}
 
-	if server, ok := creds[keyServer]; ok {
-		config[keyServer] = server
+	if creds[keyServer] == "" {
+		return nil, errors.New(errMissingServer)
+	}
+	server, err := normalizeServer(creds[keyServer])
+	if err != nil {
+		return nil, err
+	}
+	if server != creds[keyServer] {
+		logger.V(1).Info("Normalized the server", "from", creds[keyServer], "to", server)
+	}
+	config[keyServer] = server
+
+	if err := validateRFC(creds[keyRFC]); err != nil {
+		return nil, err
 	}
 
-	if rfc, ok := creds[keyRFC]; ok {
-		switch rfc {
-		case gsstsigRFC:
-			authConfig := buildGSSTSIGAuthConfig(creds)
-			config[gssapi] = []any{authConfig}
-		case keyBasedTransactionRFC:
-			secretBasedTransactionAuthConfig := buildSecretBasedTransactionAuthConfig(creds)
-			mergeMaps(config, secretBasedTransactionAuthConfig)
+	optionalConfig, err := buildOptionalConfig(creds, explicitDefaults)
+	if err != nil {
+		return nil, err
+	}
+	if err := mergeMaps(config, optionalConfig); err != nil {
+		return nil, err
+	}
+	logger.V(1).Info("Set the optional provider configuration", "keys", sortedKeys(optionalConfig), "explicitDefaults", explicitDefaults)
+
+	// The authentication configuration is merged last, so that a key it
+	// shares with the configuration above fails the build instead of
+	// replacing or being replaced by the authentication data.
+	logger.V(1).Info("Selected the authentication mode", "rfc", creds[keyRFC], "mode", authMode(creds[keyRFC]))
+	if authMode(creds[keyRFC]) == authModeUnauthenticated {
+		logger.V(1).Info("Updates are sent unauthenticated, set rfc to authenticate them", "server", server)
+	}
+	authConfig, err := buildRFCAuthConfig(creds, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := mergeMaps(config, authConfig); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// buildRFCAuthConfig builds the authentication configuration of the
+// authentication model selected by the rfc credential: the gssapi block for
+// GSS-TSIG or the key_* keys for TSIG.
+func buildRFCAuthConfig(creds map[string]string, logger logr.Logger) (map[string]any, error) {
+	switch creds[keyRFC] {
+	case gsstsigRFC:
+		if err := validateGSSTSIGCreds(creds); err != nil {
+			return nil, err
 		}
+		return map[string]any{gssapi: []any{buildGSSTSIGAuthConfig(creds)}}, nil
+	case keyBasedTransactionRFC:
+		return buildSecretBasedTransactionAuthConfig(creds, logger)
+	default:
+		// Unauthenticated updates have no authentication configuration, the
+		// update block only holds the server and the optional configuration.
+		return map[string]any{}, nil
 	}
+}
 
-	optionalConfig := buildOptionalConfig(creds)
-	mergeMaps(config, optionalConfig)
+const authModeUnauthenticated = "unauthenticated"
 
-	return config
+// authMode returns a description of the authentication mode selected by the
+// given rfc credential, for logging.
+func authMode(rfc string) string {
+	switch rfc {
+	case gsstsigRFC:
+		return "GSS-TSIG"
+	case keyBasedTransactionRFC:
+		return "TSIG"
+	default:
+		return authModeUnauthenticated
+	}
+}
+
+// sortedKeys returns the keys of the given map in sorted order.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // buildGSSTSIGAuthConfig builds the configuration for GSS-TSIG authentication (RFC 3645).
@@ -222,7 +589,7 @@ func buildGSSTSIGAuthConfig(creds map[string]string) map[string]any {
 	}
 
 	if username, ok := creds[keyUsername]; ok {
-		config[keyUsername] = username
+		config[keyUsername] = bareUsername(username)
 	}
 
 	if password, ok := creds[keyPassword]; ok {
@@ -236,15 +603,67 @@ func buildGSSTSIGAuthConfig(creds map[string]string) map[string]any {
 	return config
 }
 
+// validateGSSTSIGCreds checks that the credentials hold the realm and
+// username required for GSS-TSIG authentication and exactly one of password
+// or keytab.
+func validateGSSTSIGCreds(creds map[string]string) error {
+	var missing []string
+	for _, key := range []string{keyRealm, keyUsername} {
+		if creds[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.Errorf(errMissingGSSTSIGKeys, strings.Join(missing, ", "))
+	}
+
+	if (creds[keyPassword] == "") == (creds[keyTab] == "") {
+		return errors.Errorf(errGSSTSIGAuthMethod, keyPassword, keyTab)
+	}
+
+	if i := strings.LastIndex(creds[keyUsername], "@"); i >= 0 {
+		if realm := creds[keyUsername][i+1:]; !strings.EqualFold(realm, creds[keyRealm]) {
+			return errors.Errorf(errUsernameRealmConflict, creds[keyUsername], realm, creds[keyRealm])
+		}
+	}
+	return nil
+}
+
+// bareUsername returns the given username without the @REALM suffix of a
+// principal name, since the provider appends the configured realm itself.
+// validateGSSTSIGCreds ensures that a suffix matches the configured realm.
+func bareUsername(username string) string {
+	if i := strings.LastIndex(username, "@"); i >= 0 {
+		return username[:i]
+	}
+	return username
+}
+
 // buildSecretBasedTransactionAuthConfig builds the configuration for secret-based transaction authentication (RFC 2845).
-func buildSecretBasedTransactionAuthConfig(creds map[string]string) map[string]any {
+func buildSecretBasedTransactionAuthConfig(creds map[string]string, logger logr.Logger) (map[string]any, error) {
 	config := make(map[string]any)
 
 	if keyName, ok := creds[transcationKeyName]; ok {
-		config[transcationKeyName] = keyName
+		fqdn, err := normalizeKeyName(keyName)
+		if err != nil {
+			return nil, err
+		}
+		if fqdn != keyName {
+			logger.V(1).Info("Normalized the key_name to a fully qualified domain name", "from", keyName, "to", fqdn)
+		}
+		config[transcationKeyName] = fqdn
 	}
 
 	if keyAlgorithm, ok := creds[transactionKeyAlgorithm]; ok {
+		// algorithms are domain names on the wire, but the provider only
+		// accepts them without the trailing dot.
+		if trimmed := strings.TrimSuffix(keyAlgorithm, "."); trimmed != keyAlgorithm {
+			logger.V(1).Info("Removed the trailing dot of the key_algorithm", "key_algorithm", trimmed)
+			keyAlgorithm = trimmed
+		}
+		if err := validateKeyAlgorithm(keyAlgorithm); err != nil {
+			return nil, err
+		}
 		config[transactionKeyAlgorithm] = keyAlgorithm
 	}
 
@@ -252,35 +671,236 @@ func buildSecretBasedTransactionAuthConfig(creds map[string]string) map[string]a
 		config[transactionKeySecret] = keySecret
 	}
 
-	return config
+	return config, nil
+}
+
+// normalizeKeyName returns the given TSIG key name as a fully qualified domain
+// name, which the provider requires, appending the trailing dot when missing.
+func normalizeKeyName(name string) (string, error) {
+	fqdn := strings.TrimSuffix(name, ".")
+	if fqdn == "" {
+		return "", errors.Errorf(errInvalidKeyName, name)
+	}
+	for _, label := range strings.Split(fqdn, ".") {
+		if !isKeyNameLabel(label) {
+			return "", errors.Errorf(errInvalidKeyName, name)
+		}
+	}
+	return fqdn + ".", nil
+}
+
+// isKeyNameLabel reports whether the given label is a valid label of a key
+// name: 1 to 63 letters, digits, hyphens or underscores.
+func isKeyNameLabel(label string) bool {
+	if len(label) == 0 || len(label) > maxKeyNameLabelLength {
+		return false
+	}
+	for _, r := range label {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
 }
 
 // buildOptionalConfig builds the optional configuration for the provider.
-func buildOptionalConfig(creds map[string]string) map[string]any {
+// Absent keys are omitted, leaving them to the provider defaults and its
+// DNS_UPDATE_* environment variables, unless explicitDefaults is set, in which
+// case they are set to the documented defaults.
+func buildOptionalConfig(creds map[string]string, explicitDefaults bool) (map[string]any, error) {
 	config := make(map[string]any)
+	if explicitDefaults {
+		config[keyPort] = defaultPort
+		config[keyRetries] = defaultRetries
+		config[keyTimeout] = defaultTimeout
+		config[keyTransport] = defaultTransport
+	}
 
-	if port, ok := creds[keyPort]; ok {
+	port, err := resolvePort(creds)
+	if err != nil {
+		return nil, err
+	}
+	if port != 0 {
 		config[keyPort] = port
 	}
 
-	if retries, ok := creds[keyRetries]; ok {
+	if value, ok := creds[keyRetries]; ok {
+		retries, err := parseRetries(value)
+		if err != nil {
+			return nil, err
+		}
 		config[keyRetries] = retries
 	}
 
-	if timeout, ok := creds[keyTimeout]; ok {
+	// Unlike port and retries, timeout is a string in the provider schema,
+	// accepting both durations such as "5s" and a number of seconds.
+	//
+	// The timeout is also the only bound on an update to an unresponsive
+	// server, as the apply runs in the generated Terraform clients where no
+	// deadline can be added. The DNS provider sets it on every exchange and
+	// makes at most retries+1 attempts, so an update fails after at most
+	// (retries + 1) * timeout.
+	if value, ok := creds[keyTimeout]; ok {
+		timeout, err := parseTimeout(value)
+		if err != nil {
+			return nil, err
+		}
 		config[keyTimeout] = timeout
 	}
 
 	if transport, ok := creds[keyTransport]; ok {
+		if err := validateTransport(transport); err != nil {
+			return nil, err
+		}
 		config[keyTransport] = transport
 	}
 
-	return config
+	return config, nil
+}
+
+// resolvePort returns the port updates are sent to for the configured transport.
+// A transport specific tcp_port or udp_port overrides port; when neither applies
+// port is used, and a zero result leaves the provider default (53) in place.
+func resolvePort(creds map[string]string) (int, error) {
+	ports := map[string]int{}
+	for _, key := range []string{keyTCPPort, keyUDPPort, keyPort} {
+		if value, ok := creds[key]; ok {
+			port, err := parsePort(key, value)
+			if err != nil {
+				return 0, err
+			}
+			ports[key] = port
+		}
+	}
+
+	transport := creds[keyTransport]
+	if transport == "" {
+		transport = defaultTransport
+	}
+
+	key := keyUDPPort
+	if strings.HasPrefix(transport, tcpTransport) {
+		key = keyTCPPort
+	}
+
+	if port, ok := ports[key]; ok {
+		return port, nil
+	}
+
+	return ports[keyPort], nil
+}
+
+// parseRetries parses the retries credential.
+// Matching the provider, retries counts the attempts made after the initial one when
+// an exchange times out or fails with SERVFAIL: "3" allows up to four exchanges and
+// "0" disables retrying.
+func parseRetries(value string) (int, error) {
+	retries, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || retries < 0 {
+		return 0, errors.Errorf(errInvalidRetries, value)
+	}
+	return retries, nil
+}
+
+// clampRetries lowers the retries credential to the maxRetries of the given
+// ProviderConfig spec and reports whether it did. Invalid retries are left
+// for buildOptionalConfig to report.
+func clampRetries(pcSpec *namespacedv1beta1.ProviderConfigSpec, creds map[string]string) bool {
+	value, ok := creds[keyRetries]
+	if !ok {
+		return false
+	}
+	retries, err := parseRetries(value)
+	if err != nil {
+		return false
+	}
+
+	limit := defaultMaxRetries
+	if pcSpec.MaxRetries != nil {
+		limit = *pcSpec.MaxRetries
+	}
+	if retries <= limit {
+		return false
+	}
+	creds[keyRetries] = strconv.Itoa(limit)
+	return true
+}
+
+// validateTransport checks that the given transport is supported by the
+// provider.
+func validateTransport(transport string) error {
+	for _, t := range transports {
+		if transport == t {
+			return nil
+		}
+	}
+	return errors.Errorf(errInvalidTransport, transport, strings.Join(transports, ", "))
+}
+
+// validateRFC checks that the rfc credential selects a supported
+// authentication model. An absent rfc or none is valid and sends
+// unauthenticated updates, which RFC 2136 allows.
+func validateRFC(rfc string) error {
+	switch rfc {
+	case "", unauthenticatedRFC, gsstsigRFC, keyBasedTransactionRFC:
+		return nil
+	default:
+		return errors.Errorf(errInvalidRFC, rfc, gsstsigRFC, keyBasedTransactionRFC, unauthenticatedRFC)
+	}
+}
+
+// parseTimeout validates the timeout credential, either a duration such as
+// "1500ms" or "2m", or a number of seconds. Durations are returned in their
+// canonical form and numbers of seconds unchanged, both of which the
+// provider parses.
+func parseTimeout(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return "", errors.Errorf(errInvalidTimeout, value)
+		}
+		return value, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return "", errors.Errorf(errInvalidTimeout, value)
+	}
+	return d.String(), nil
+}
+
+// validateKeyAlgorithm checks that the given TSIG algorithm is supported.
+func validateKeyAlgorithm(algorithm string) error {
+	for _, a := range keyAlgorithms {
+		if algorithm == a {
+			return nil
+		}
+	}
+	return errors.Errorf(errInvalidKeyAlgorithm, algorithm, strings.Join(keyAlgorithms, ", "))
 }
 
-// mergeMaps merges all keys from map b into map a.
-func mergeMaps(a, b map[string]any) {
+// parsePort parses the value of the given key as a port number.
+func parsePort(key, value string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || port < 1 || port > maxPort {
+		return 0, errors.Errorf(errInvalidPort, key, value)
+	}
+	return port, nil
+}
+
+// mergeMaps merges all keys from map b into map a. It returns an error naming
+// the first key, in sorted order, that is set in both maps, leaving map a
+// unchanged.
+func mergeMaps(a, b map[string]any) error {
+	for _, k := range sortedKeys(b) {
+		if _, ok := a[k]; ok {
+			return errors.Errorf(errDuplicateConfigKey, k)
+		}
+	}
+
 	for k, v := range b {
 		a[k] = v
 	}
+	return nil
 }
@@ -0,0 +1,902 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	clusterrecordsetv1alpha1 "github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1"
+	clusterv1beta1 "github.com/dana-team/provider-dns-v2/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+func TestNormalizeKeyName(t *testing.T) {
+	label64 := strings.Repeat("k", maxKeyNameLabelLength+1)
+
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		name   string
+		want   want
+	}{
+		"Relative": {
+			reason: "A key name without a trailing dot should be qualified.",
+			name:   "tsig-key.example.com",
+			want:   want{name: "tsig-key.example.com."},
+		},
+		"FQDN": {
+			reason: "A fully qualified key name should be unchanged.",
+			name:   "tsig-key.example.com.",
+			want:   want{name: "tsig-key.example.com."},
+		},
+		"SingleLabel": {
+			reason: "A single label key name should be qualified.",
+			name:   "tsig_key",
+			want:   want{name: "tsig_key."},
+		},
+		"Empty": {
+			reason: "An empty key name should be rejected.",
+			name:   ".",
+			want:   want{err: errors.Errorf(errInvalidKeyName, ".")},
+		},
+		"EmptyLabel": {
+			reason: "A key name with an empty label should be rejected.",
+			name:   "tsig..example.com",
+			want:   want{err: errors.Errorf(errInvalidKeyName, "tsig..example.com")},
+		},
+		"InvalidCharacter": {
+			reason: "A key name with characters other than letters, digits, hyphens and underscores should be rejected.",
+			name:   "tsig key.example.com",
+			want:   want{err: errors.Errorf(errInvalidKeyName, "tsig key.example.com")},
+		},
+		"LabelTooLong": {
+			reason: "A key name with a label longer than 63 characters should be rejected.",
+			name:   label64 + ".example.com",
+			want:   want{err: errors.Errorf(errInvalidKeyName, label64+".example.com")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := normalizeKeyName(tc.name)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nnormalizeKeyName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("\n%s\nnormalizeKeyName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBuildSecretBasedTransactionAuthConfig(t *testing.T) {
+	type want struct {
+		config map[string]any
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		creds  map[string]string
+		want   want
+	}{
+		"NormalizesKeyName": {
+			reason: "The key_name should be qualified and the trailing dot of the key_algorithm removed.",
+			creds: map[string]string{
+				transcationKeyName:      "tsig-key.example.com",
+				transactionKeyAlgorithm: "hmac-sha256.",
+				transactionKeySecret:    "c2VjcmV0",
+			},
+			want: want{config: map[string]any{
+				transcationKeyName:      "tsig-key.example.com.",
+				transactionKeyAlgorithm: "hmac-sha256",
+				transactionKeySecret:    "c2VjcmV0",
+			}},
+		},
+		"InvalidKeyName": {
+			reason: "An invalid key_name should be rejected.",
+			creds:  map[string]string{transcationKeyName: "tsig key"},
+			want:   want{err: errors.Errorf(errInvalidKeyName, "tsig key")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := buildSecretBasedTransactionAuthConfig(tc.creds, logr.Discard())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nbuildSecretBasedTransactionAuthConfig(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.config, got); diff != "" {
+				t.Errorf("\n%s\nbuildSecretBasedTransactionAuthConfig(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolvePort(t *testing.T) {
+	type want struct {
+		port int
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		creds  map[string]string
+		want   want
+	}{
+		"NoPort": {
+			reason: "Without any port the provider default should be left in place.",
+			creds:  map[string]string{},
+			want:   want{port: 0},
+		},
+		"Port": {
+			reason: "The port should be used when no transport specific port applies.",
+			creds:  map[string]string{keyPort: "5353"},
+			want:   want{port: 5353},
+		},
+		"UDPPortDefaultTransport": {
+			reason: "The udp_port should override the port for the default transport.",
+			creds:  map[string]string{keyPort: "5353", keyUDPPort: "5354", keyTCPPort: "5355"},
+			want:   want{port: 5354},
+		},
+		"UDPPort": {
+			reason: "The udp_port should override the port for the udp6 transport.",
+			creds:  map[string]string{keyPort: "5353", keyUDPPort: "5354", keyTransport: "udp6"},
+			want:   want{port: 5354},
+		},
+		"TCPPort": {
+			reason: "The tcp_port should override the port for the tcp4 transport.",
+			creds:  map[string]string{keyPort: "5353", keyUDPPort: "5354", keyTCPPort: "5355", keyTransport: "tcp4"},
+			want:   want{port: 5355},
+		},
+		"TCPPortOtherTransport": {
+			reason: "The tcp_port should not apply to a UDP transport, which falls back to the port.",
+			creds:  map[string]string{keyPort: "5353", keyTCPPort: "5355", keyTransport: "udp"},
+			want:   want{port: 5353},
+		},
+		"TCPPortWithoutPort": {
+			reason: "Without a port, a transport that has no specific port should keep the provider default.",
+			creds:  map[string]string{keyUDPPort: "5354", keyTransport: "tcp"},
+			want:   want{port: 0},
+		},
+		"Whitespace": {
+			reason: "Surrounding whitespace should be ignored.",
+			creds:  map[string]string{keyPort: " 5353 "},
+			want:   want{port: 5353},
+		},
+		"NotANumber": {
+			reason: "A port that is not a number should be rejected.",
+			creds:  map[string]string{keyPort: "dns"},
+			want:   want{err: errors.Errorf(errInvalidPort, keyPort, "dns")},
+		},
+		"Zero": {
+			reason: "Port 0 should be rejected.",
+			creds:  map[string]string{keyUDPPort: "0"},
+			want:   want{err: errors.Errorf(errInvalidPort, keyUDPPort, "0")},
+		},
+		"TooLarge": {
+			reason: "A port above 65535 should be rejected.",
+			creds:  map[string]string{keyTCPPort: "65536"},
+			want:   want{err: errors.Errorf(errInvalidPort, keyTCPPort, "65536")},
+		},
+		"InvalidUnusedPort": {
+			reason: "An invalid port should be rejected even when the transport does not use it.",
+			creds:  map[string]string{keyPort: "53", keyTCPPort: "-1"},
+			want:   want{err: errors.Errorf(errInvalidPort, keyTCPPort, "-1")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := resolvePort(tc.creds)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nresolvePort(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.port, got); diff != "" {
+				t.Errorf("\n%s\nresolvePort(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseRetries(t *testing.T) {
+	type want struct {
+		retries int
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		value  string
+		want   want
+	}{
+		"Zero": {
+			reason: "Zero should disable retrying.",
+			value:  "0",
+			want:   want{retries: 0},
+		},
+		"Retries": {
+			reason: "A number of retries should be passed to the provider unchanged, as it counts the retries after the initial attempt.",
+			value:  "3",
+			want:   want{retries: 3},
+		},
+		"Whitespace": {
+			reason: "Surrounding whitespace should be ignored.",
+			value:  " 2 ",
+			want:   want{retries: 2},
+		},
+		"Negative": {
+			reason: "Negative retries should be rejected.",
+			value:  "-1",
+			want:   want{err: errors.Errorf(errInvalidRetries, "-1")},
+		},
+		"NotANumber": {
+			reason: "Retries that are not a number should be rejected, naming the value.",
+			value:  "three",
+			want:   want{err: errors.Errorf(errInvalidRetries, "three")},
+		},
+		"Fraction": {
+			reason: "Fractional retries should be rejected.",
+			value:  "1.5",
+			want:   want{err: errors.Errorf(errInvalidRetries, "1.5")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseRetries(tc.value)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nparseRetries(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.retries, got); diff != "" {
+				t.Errorf("\n%s\nparseRetries(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClampRetries(t *testing.T) {
+	type want struct {
+		creds   map[string]string
+		clamped bool
+	}
+
+	cases := map[string]struct {
+		reason     string
+		maxRetries *int
+		creds      map[string]string
+		want       want
+	}{
+		"Unset": {
+			reason: "Credentials without retries should be left unchanged.",
+			creds:  map[string]string{},
+			want:   want{creds: map[string]string{}},
+		},
+		"WithinDefault": {
+			reason: "Retries up to the default maximum should be left unchanged.",
+			creds:  map[string]string{keyRetries: "10"},
+			want:   want{creds: map[string]string{keyRetries: "10"}},
+		},
+		"AboveDefault": {
+			reason: "Retries above the default maximum should be lowered to it.",
+			creds:  map[string]string{keyRetries: "1000"},
+			want:   want{creds: map[string]string{keyRetries: "10"}, clamped: true},
+		},
+		"WithinMaxRetries": {
+			reason:     "Retries up to the maxRetries of the ProviderConfig should be left unchanged.",
+			maxRetries: to(20),
+			creds:      map[string]string{keyRetries: "15"},
+			want:       want{creds: map[string]string{keyRetries: "15"}},
+		},
+		"AboveMaxRetries": {
+			reason:     "Retries above the maxRetries of the ProviderConfig should be lowered to it.",
+			maxRetries: to(2),
+			creds:      map[string]string{keyRetries: "3"},
+			want:       want{creds: map[string]string{keyRetries: "2"}, clamped: true},
+		},
+		"ZeroMaxRetries": {
+			reason:     "A maxRetries of zero should disable retrying.",
+			maxRetries: to(0),
+			creds:      map[string]string{keyRetries: "3"},
+			want:       want{creds: map[string]string{keyRetries: "0"}, clamped: true},
+		},
+		"Negative": {
+			reason: "Negative retries should be left for buildOptionalConfig to reject.",
+			creds:  map[string]string{keyRetries: "-1"},
+			want:   want{creds: map[string]string{keyRetries: "-1"}},
+		},
+		"NotANumber": {
+			reason: "Retries that are not a number should be left for buildOptionalConfig to reject.",
+			creds:  map[string]string{keyRetries: "many"},
+			want:   want{creds: map[string]string{keyRetries: "many"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clamped := clampRetries(&namespacedv1beta1.ProviderConfigSpec{MaxRetries: tc.maxRetries}, tc.creds)
+			if diff := cmp.Diff(tc.want.clamped, clamped); diff != "" {
+				t.Errorf("\n%s\nclampRetries(...): -want clamped, +got clamped:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, tc.creds); diff != "" {
+				t.Errorf("\n%s\nclampRetries(...): -want credentials, +got credentials:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	type want struct {
+		timeout string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		value  string
+		want   want
+	}{
+		"Seconds": {
+			reason: "A number of seconds should be passed to the provider unchanged.",
+			value:  "30",
+			want:   want{timeout: "30"},
+		},
+		"Zero": {
+			reason: "A zero timeout should be allowed.",
+			value:  "0",
+			want:   want{timeout: "0"},
+		},
+		"Duration": {
+			reason: "A duration should be passed to the provider in its canonical form.",
+			value:  "1500ms",
+			want:   want{timeout: "1.5s"},
+		},
+		"CompoundDuration": {
+			reason: "A compound duration should be canonicalized.",
+			value:  "1m30s",
+			want:   want{timeout: "1m30s"},
+		},
+		"Minutes": {
+			reason: "A duration in minutes should be canonicalized.",
+			value:  "2m",
+			want:   want{timeout: "2m0s"},
+		},
+		"Whitespace": {
+			reason: "Surrounding whitespace should be ignored.",
+			value:  " 5s ",
+			want:   want{timeout: "5s"},
+		},
+		"NegativeSeconds": {
+			reason: "A negative number of seconds should be rejected.",
+			value:  "-1",
+			want:   want{err: errors.Errorf(errInvalidTimeout, "-1")},
+		},
+		"NegativeDuration": {
+			reason: "A negative duration should be rejected.",
+			value:  "-5s",
+			want:   want{err: errors.Errorf(errInvalidTimeout, "-5s")},
+		},
+		"MissingUnit": {
+			reason: "A fractional number without a unit should be rejected.",
+			value:  "1.5",
+			want:   want{err: errors.Errorf(errInvalidTimeout, "1.5")},
+		},
+		"UnknownUnit": {
+			reason: "A duration with an unknown unit should be rejected.",
+			value:  "5d",
+			want:   want{err: errors.Errorf(errInvalidTimeout, "5d")},
+		},
+		"Empty": {
+			reason: "An empty timeout should be rejected.",
+			value:  "",
+			want:   want{err: errors.Errorf(errInvalidTimeout, "")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseTimeout(tc.value)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nparseTimeout(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.timeout, got); diff != "" {
+				t.Errorf("\n%s\nparseTimeout(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestToSharedPCSpec(t *testing.T) {
+	full := &clusterv1beta1.ProviderConfig{Spec: clusterv1beta1.ProviderConfigSpec{
+		Description:          "Corporate DNS",
+		MaxRecordSetSize:     to(50),
+		TCPFallbackThreshold: to(20),
+		MaxRetries:           to(5),
+		ExplicitDefaults:     true,
+		Profiles: []clusterv1beta1.ZoneProfile{
+			{Name: "local", Zones: []string{"example.com"}, Retries: to(1), Timeout: "2s", Transport: "tcp"},
+			{Name: "remote", Zones: []string{"example.org", "example.net"}, Retries: to(4), Timeout: "10s", Transport: "udp"},
+		},
+		DefaultProfile: "local",
+		TSIGKeys: []clusterv1beta1.TSIGKey{
+			{Name: "transfer", KeyName: "transfer.example.com.", KeyAlgorithm: "hmac-sha512", SecretKey: "transfer_secret"},
+		},
+		Connection: &clusterv1beta1.ConnectionSettings{Server: "ns1.example.com", Port: to(5353), RFC: "2845", Transport: "tcp", Retries: to(2), Timeout: "5s"},
+		Credentials: clusterv1beta1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+				Fs:        &xpv1.FsSelector{Path: "/etc/dns/credentials"},
+				Env:       &xpv1.EnvSelector{Name: "DNS_CREDENTIALS"},
+				SecretRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "dns-creds", Namespace: "crossplane-system"}, Key: "credentials"},
+			},
+			SecretClusterRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "kubeconfig", Namespace: "crossplane-system"}, Key: "kubeconfig"},
+		},
+	}}
+	// Every field must be set, so that a field added to the spec but not to
+	// toSharedPCSpec fails the test.
+	checkPopulated(t, reflect.ValueOf(full.Spec), "spec")
+
+	want := &namespacedv1beta1.ProviderConfigSpec{
+		Description:          "Corporate DNS",
+		MaxRecordSetSize:     to(50),
+		TCPFallbackThreshold: to(20),
+		MaxRetries:           to(5),
+		ExplicitDefaults:     true,
+		Profiles: []namespacedv1beta1.ZoneProfile{
+			{Name: "local", Zones: []string{"example.com"}, Retries: to(1), Timeout: "2s", Transport: "tcp"},
+			{Name: "remote", Zones: []string{"example.org", "example.net"}, Retries: to(4), Timeout: "10s", Transport: "udp"},
+		},
+		DefaultProfile: "local",
+		TSIGKeys: []namespacedv1beta1.TSIGKey{
+			{Name: "transfer", KeyName: "transfer.example.com.", KeyAlgorithm: "hmac-sha512", SecretKey: "transfer_secret"},
+		},
+		Connection: &namespacedv1beta1.ConnectionSettings{Server: "ns1.example.com", Port: to(5353), RFC: "2845", Transport: "tcp", Retries: to(2), Timeout: "5s"},
+		Credentials: namespacedv1beta1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+				Fs:        &xpv1.FsSelector{Path: "/etc/dns/credentials"},
+				Env:       &xpv1.EnvSelector{Name: "DNS_CREDENTIALS"},
+				SecretRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "dns-creds", Namespace: "crossplane-system"}, Key: "credentials"},
+			},
+			SecretClusterRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "kubeconfig", Namespace: "crossplane-system"}, Key: "kubeconfig"},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		pc     *clusterv1beta1.ProviderConfig
+		want   *namespacedv1beta1.ProviderConfigSpec
+	}{
+		"Nil": {
+			reason: "A nil ProviderConfig should have a nil spec.",
+		},
+		"Empty": {
+			reason: "An empty spec should be converted to an empty spec.",
+			pc:     &clusterv1beta1.ProviderConfig{},
+			want:   &namespacedv1beta1.ProviderConfigSpec{},
+		},
+		"Full": {
+			reason: "Every field of the spec should be carried over.",
+			pc:     full,
+			want:   want,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := toSharedPCSpec(tc.pc)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ntoSharedPCSpec(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	t.Run("Copy", func(t *testing.T) {
+		pc := full.DeepCopy()
+		got := toSharedPCSpec(pc)
+		*got.MaxRetries = 0
+		got.Profiles[0].Zones[0] = "changed"
+		got.Credentials.SecretRef.Name = "changed"
+		if diff := cmp.Diff(full, pc); diff != "" {
+			t.Errorf("toSharedPCSpec(...): modifying the result must not modify the ProviderConfig: -want, +got:\n%s", diff)
+		}
+	})
+}
+
+// checkPopulated fails the test for every field of the given value, and of
+// the values it points to or holds, that is not set.
+func checkPopulated(t *testing.T, v reflect.Value, path string) {
+	t.Helper()
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			t.Errorf("%s is not set", path)
+			return
+		}
+		checkPopulated(t, v.Elem(), path)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			checkPopulated(t, v.Field(i), path+"."+v.Type().Field(i).Name)
+		}
+	case reflect.Slice:
+		if v.Len() == 0 {
+			t.Errorf("%s is not set", path)
+			return
+		}
+		for i := range v.Len() {
+			checkPopulated(t, v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		if v.IsZero() {
+			t.Errorf("%s is not set", path)
+		}
+	}
+}
+
+func TestBuildAuthConfigMatrix(t *testing.T) {
+	type variant struct {
+		creds map[string]string
+		want  map[string]any
+	}
+
+	auths := map[string]variant{
+		"GSSTSIGPassword": {
+			creds: map[string]string{keyRFC: gsstsigRFC, keyRealm: "EXAMPLE.COM", keyUsername: "dns", keyPassword: "passw0rd"},
+			want:  map[string]any{gssapi: []any{map[string]any{keyRealm: "EXAMPLE.COM", keyUsername: "dns", keyPassword: "passw0rd"}}},
+		},
+		"GSSTSIGKeytab": {
+			creds: map[string]string{keyRFC: gsstsigRFC, keyRealm: "EXAMPLE.COM", keyUsername: "dns", keyTab: "/etc/dns.keytab"},
+			want:  map[string]any{gssapi: []any{map[string]any{keyRealm: "EXAMPLE.COM", keyUsername: "dns", keyTab: "/etc/dns.keytab"}}},
+		},
+		"SecretTSIG": {
+			creds: map[string]string{keyRFC: keyBasedTransactionRFC, transcationKeyName: "tsig.example.com.", transactionKeyAlgorithm: "hmac-sha256", transactionKeySecret: "c2VjcmV0"},
+			want:  map[string]any{transcationKeyName: "tsig.example.com.", transactionKeyAlgorithm: "hmac-sha256", transactionKeySecret: "c2VjcmV0"},
+		},
+		"Unauthenticated": {
+			creds: map[string]string{keyRFC: unauthenticatedRFC},
+			want:  map[string]any{},
+		},
+	}
+
+	optionals := map[string]struct {
+		variant
+		explicitDefaults bool
+	}{
+		"None": {variant: variant{creds: map[string]string{}, want: map[string]any{}}},
+		"Port": {variant: variant{
+			creds: map[string]string{keyPort: "5353"},
+			want:  map[string]any{keyPort: 5353},
+		}},
+		"UDPPort": {variant: variant{
+			creds: map[string]string{keyPort: "5353", keyUDPPort: "5300"},
+			want:  map[string]any{keyPort: 5300},
+		}},
+		"TCPPort": {variant: variant{
+			creds: map[string]string{keyTCPPort: "5301", keyTransport: "tcp"},
+			want:  map[string]any{keyPort: 5301, keyTransport: "tcp"},
+		}},
+		"Retries": {variant: variant{
+			creds: map[string]string{keyRetries: "2"},
+			want:  map[string]any{keyRetries: 2},
+		}},
+		"Timeout": {variant: variant{
+			creds: map[string]string{keyTimeout: "5s"},
+			want:  map[string]any{keyTimeout: "5s"},
+		}},
+		"Transport": {variant: variant{
+			creds: map[string]string{keyTransport: "tcp6"},
+			want:  map[string]any{keyTransport: "tcp6"},
+		}},
+		"All": {variant: variant{
+			creds: map[string]string{keyPort: "5353", keyRetries: "1", keyTimeout: "30", keyTransport: "udp4"},
+			want:  map[string]any{keyPort: 5353, keyRetries: 1, keyTimeout: "30", keyTransport: "udp4"},
+		}},
+		"ExplicitDefaults": {explicitDefaults: true, variant: variant{
+			creds: map[string]string{},
+			want:  map[string]any{keyPort: defaultPort, keyRetries: defaultRetries, keyTimeout: defaultTimeout, keyTransport: defaultTransport},
+		}},
+		"ExplicitDefaultsOverridden": {explicitDefaults: true, variant: variant{
+			creds: map[string]string{keyPort: "5353", keyTransport: "tcp"},
+			want:  map[string]any{keyPort: 5353, keyRetries: defaultRetries, keyTimeout: defaultTimeout, keyTransport: "tcp"},
+		}},
+	}
+
+	for authName, auth := range auths {
+		for optionalName, optional := range optionals {
+			t.Run(authName+"/"+optionalName, func(t *testing.T) {
+				creds := map[string]string{keyServer: "ns1.example.com"}
+				want := map[string]any{keyServer: "ns1.example.com"}
+				for _, v := range []variant{auth, optional.variant} {
+					for k, value := range v.creds {
+						creds[k] = value
+					}
+					for k, value := range v.want {
+						want[k] = value
+					}
+				}
+
+				got, err := buildAuthConfig(creds, optional.explicitDefaults, logr.Discard())
+				if err != nil {
+					t.Fatalf("buildAuthConfig(...): %v", err)
+				}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("buildAuthConfig(...): -want, +got:\n%s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestMergeMaps(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		a      map[string]any
+		b      map[string]any
+		want   map[string]any
+		err    error
+	}{
+		"Disjoint": {
+			reason: "Maps without shared keys should be merged.",
+			a:      map[string]any{keyServer: "ns1.example.com", keyPort: 53},
+			b:      map[string]any{gssapi: []any{map[string]any{keyRealm: "EXAMPLE.COM"}}},
+			want:   map[string]any{keyServer: "ns1.example.com", keyPort: 53, gssapi: []any{map[string]any{keyRealm: "EXAMPLE.COM"}}},
+		},
+		"Duplicate": {
+			reason: "A shared key should fail the merge instead of dropping either value.",
+			a:      map[string]any{keyServer: "ns1.example.com", keyPort: 53},
+			b:      map[string]any{keyPort: 5353},
+			want:   map[string]any{keyServer: "ns1.example.com", keyPort: 53},
+			err:    errors.Errorf(errDuplicateConfigKey, keyPort),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := mergeMaps(tc.a, tc.b)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nmergeMaps(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.a); diff != "" {
+				t.Errorf("\n%s\nmergeMaps(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestResolveProviderConfig(t *testing.T) {
+	const description = "the default ProviderConfig"
+
+	legacy := func(ref *xpv1.Reference) resource.Managed {
+		rs := &clusterrecordsetv1alpha1.ARecordSet{}
+		rs.SetName("www")
+		rs.SetUID("legacy-uid")
+		rs.SetProviderConfigReference(ref)
+		return rs
+	}
+	modern := func(ref *xpv1.ProviderConfigReference) resource.Managed {
+		rs := testRecordSet()
+		rs.SetProviderConfigReference(ref)
+		return rs
+	}
+	clusterPC := &clusterv1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Spec: clusterv1beta1.ProviderConfigSpec{Description: description}}
+	namespacedPC := testProviderConfigWith(func(spec *namespacedv1beta1.ProviderConfigSpec) { spec.Description = description })
+	clusterProviderConfig := &namespacedv1beta1.ClusterProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Spec: namespacedv1beta1.ProviderConfigSpec{Description: description}}
+
+	type want struct {
+		kind        string
+		description string
+		err         error
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		want   want
+	}{
+		"LegacyNoReference": {
+			reason: "A cluster-scoped resource without a providerConfigRef should fail rather than fall back to a ProviderConfig.",
+			mg:     legacy(nil),
+			want:   want{err: errors.New(errNoProviderConfig)},
+		},
+		"LegacyDefault": {
+			reason: "A cluster-scoped resource should resolve the ProviderConfig named default that its providerConfigRef defaults to.",
+			mg:     legacy(&xpv1.Reference{Name: "default"}),
+			want:   want{kind: clusterv1beta1.ProviderConfigKind, description: description},
+		},
+		"ModernNoReference": {
+			reason: "A namespaced resource without a providerConfigRef should fail rather than fall back to a ProviderConfig.",
+			mg:     modern(nil),
+			want:   want{err: errors.New(errNoProviderConfig)},
+		},
+		"ModernDefault": {
+			reason: "A namespaced resource should resolve the ClusterProviderConfig named default that its providerConfigRef defaults to.",
+			mg:     modern(&xpv1.ProviderConfigReference{Kind: namespacedv1beta1.ClusterProviderConfigKind, Name: "default"}),
+			want:   want{kind: namespacedv1beta1.ClusterProviderConfigKind, description: description},
+		},
+		"ModernUnknownKind": {
+			reason: "A namespaced resource whose providerConfigRef has an unknown kind should fail before any lookup.",
+			mg:     modern(&xpv1.ProviderConfigReference{Kind: "Provider", Name: "default"}),
+			want:   want{err: errors.Errorf(errUnknownProviderConfigKind, "Provider", namespacedv1beta1.ProviderConfigKind, namespacedv1beta1.ClusterProviderConfigKind)},
+		},
+		"ModernProviderConfig": {
+			reason: "A namespaced resource should resolve the ProviderConfig of its namespace when the providerConfigRef selects one.",
+			mg:     modern(&xpv1.ProviderConfigReference{Kind: namespacedv1beta1.ProviderConfigKind, Name: testConfigName}),
+			want:   want{kind: namespacedv1beta1.ProviderConfigKind, description: description},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := testClient(t, clusterPC.DeepCopy(), namespacedPC.DeepCopy(), clusterProviderConfig.DeepCopy())
+			pc, pcSpec, err := resolveProviderConfig(context.Background(), c, tc.mg, newUsageDebouncer(0))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nresolveProviderConfig(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			gvk, err := apiutil.GVKForObject(pc, c.Scheme())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.kind, gvk.Kind); diff != "" {
+				t.Errorf("\n%s\nresolveProviderConfig(...): -want kind, +got kind:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.description, pcSpec.Description); diff != "" {
+				t.Errorf("\n%s\nresolveProviderConfig(...): -want description, +got description:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateGSSTSIGCreds(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		creds  map[string]string
+		want   error
+	}{
+		"BareUsername": {
+			reason: "A bare username should be accepted.",
+			creds:  map[string]string{keyRealm: "EXAMPLE.COM", keyUsername: "dns", keyPassword: "p4ssw0rd"},
+		},
+		"MatchingRealm": {
+			reason: "A username with the configured realm as suffix should be accepted.",
+			creds:  map[string]string{keyRealm: "EXAMPLE.COM", keyUsername: "dns@EXAMPLE.COM", keyPassword: "p4ssw0rd"},
+		},
+		"MatchingRealmCase": {
+			reason: "The realm suffix of a username should match the configured realm case-insensitively.",
+			creds:  map[string]string{keyRealm: "EXAMPLE.COM", keyUsername: "dns@example.com", keyTab: "/etc/krb5/dns.keytab"},
+		},
+		"ConflictingRealm": {
+			reason: "A username with another realm as suffix should be rejected.",
+			creds:  map[string]string{keyRealm: "EXAMPLE.COM", keyUsername: "dns@OTHER.COM", keyPassword: "p4ssw0rd"},
+			want:   errors.Errorf(errUsernameRealmConflict, "dns@OTHER.COM", "OTHER.COM", "EXAMPLE.COM"),
+		},
+		"EmptyRealmSuffix": {
+			reason: "A username with an empty realm suffix should be rejected.",
+			creds:  map[string]string{keyRealm: "EXAMPLE.COM", keyUsername: "dns@", keyPassword: "p4ssw0rd"},
+			want:   errors.Errorf(errUsernameRealmConflict, "dns@", "", "EXAMPLE.COM"),
+		},
+		"MissingKeys": {
+			reason: "Credentials without a realm and username should be rejected, naming both.",
+			creds:  map[string]string{keyPassword: "p4ssw0rd"},
+			want:   errors.Errorf(errMissingGSSTSIGKeys, "realm, username"),
+		},
+		"NoAuthMethod": {
+			reason: "Credentials without a password or keytab should be rejected.",
+			creds:  map[string]string{keyRealm: "EXAMPLE.COM", keyUsername: "dns"},
+			want:   errors.Errorf(errGSSTSIGAuthMethod, keyPassword, keyTab),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateGSSTSIGCreds(tc.creds)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalidateGSSTSIGCreds(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBareUsername(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		username string
+		want     string
+	}{
+		"Bare": {
+			reason:   "A bare username should be kept.",
+			username: "dns",
+			want:     "dns",
+		},
+		"RealmSuffix": {
+			reason:   "The realm suffix of a principal name should be stripped.",
+			username: "dns@EXAMPLE.COM",
+			want:     "dns",
+		},
+		"Instance": {
+			reason:   "The instance of a principal name should be kept.",
+			username: "dns/ns1.example.com@EXAMPLE.COM",
+			want:     "dns/ns1.example.com",
+		},
+		"LastAt": {
+			reason:   "Only the suffix after the last @ should be stripped.",
+			username: "dns@corp@EXAMPLE.COM",
+			want:     "dns@corp",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, bareUsername(tc.username)); diff != "" {
+				t.Errorf("\n%s\nbareUsername(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProviderConfigKind(t *testing.T) {
+	type want struct {
+		kind string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kind   string
+		want   want
+	}{
+		"Empty": {
+			reason: "An empty kind should default to ProviderConfig.",
+			want:   want{kind: namespacedv1beta1.ProviderConfigKind},
+		},
+		"ProviderConfig": {
+			reason: "The ProviderConfig kind should be accepted.",
+			kind:   namespacedv1beta1.ProviderConfigKind,
+			want:   want{kind: namespacedv1beta1.ProviderConfigKind},
+		},
+		"ClusterProviderConfig": {
+			reason: "The ClusterProviderConfig kind should be accepted.",
+			kind:   namespacedv1beta1.ClusterProviderConfigKind,
+			want:   want{kind: namespacedv1beta1.ClusterProviderConfigKind},
+		},
+		"Unknown": {
+			reason: "An unknown kind should be rejected, naming the valid kinds.",
+			kind:   "ProviderConfigUsage",
+			want:   want{err: errors.Errorf(errUnknownProviderConfigKind, "ProviderConfigUsage", namespacedv1beta1.ProviderConfigKind, namespacedv1beta1.ClusterProviderConfigKind)},
+		},
+		"Case": {
+			reason: "Kinds are case sensitive, so a kind in another case should be rejected.",
+			kind:   "providerconfig",
+			want:   want{err: errors.Errorf(errUnknownProviderConfigKind, "providerconfig", namespacedv1beta1.ProviderConfigKind, namespacedv1beta1.ClusterProviderConfigKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := providerConfigKind(tc.kind)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nproviderConfigKind(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.kind, got); diff != "" {
+				t.Errorf("\n%s\nproviderConfigKind(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
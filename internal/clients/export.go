@@ -0,0 +1,63 @@
+package clients
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// providerName is the name of the Terraform provider the configuration is
+// rendered for.
+const providerName = "dns"
+
+// ExportProviderConfig renders the given Terraform provider configuration as
+// a terraform-provider-dns provider block, so that a failing configuration can
+// be reproduced with plain Terraform. Secret values are replaced with a
+// placeholder.
+func ExportProviderConfig(config map[string]any) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "provider %q {\n", providerName)
+	writeBlockBody(&b, config, 1)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// writeBlockBody writes the attributes and nested blocks of a block at the
+// given indentation level. Lists of objects are written as repeated blocks.
+func writeBlockBody(b *strings.Builder, body map[string]any, level int) {
+	indent := strings.Repeat("  ", level)
+
+	keys := make([]string, 0, len(body))
+	for k := range body {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if blocks, ok := body[k].([]any); ok {
+			for _, block := range blocks {
+				if nested, ok := block.(map[string]any); ok {
+					fmt.Fprintf(b, "%s%s {\n", indent, k)
+					writeBlockBody(b, nested, level+1)
+					fmt.Fprintf(b, "%s}\n", indent)
+				}
+			}
+			continue
+		}
+		fmt.Fprintf(b, "%s%s = %s\n", indent, k, attributeValue(k, body[k]))
+	}
+}
+
+// attributeValue renders a single attribute value, redacting secret keys.
+func attributeValue(key string, value any) string {
+	for _, k := range secretCredentialKeys {
+		if key == k {
+			return strconv.Quote(redactedValue)
+		}
+	}
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,109 @@
+package clients
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errReadFileReference      = "cannot read the file referenced by %s"
+	errFileReferencesDisabled = "%s references a file, but file references are disabled as --credentials-file-root is not set"
+	errFileReferenceNotLocal  = "%s references %q, which is not a relative path within --credentials-file-root"
+
+	// fileReferencePrefix marks a credential value as a reference to a file
+	// on the provider's filesystem.
+	fileReferencePrefix = "file:"
+)
+
+// fileReferenceKeys are the credential keys whose value may be a file
+// reference.
+var fileReferenceKeys = []string{transactionKeySecret, keyPassword, keyTab}
+
+// credentialsFileRoot is the directory file references are resolved in. File
+// references are rejected while it is empty, so that the credentials of a
+// ProviderConfig cannot read arbitrary files of the provider.
+var credentialsFileRoot string
+
+// SetCredentialsFileRoot sets the directory the file references of the
+// credentials are resolved in. It must be called before any credentials are
+// resolved.
+func SetCredentialsFileRoot(dir string) {
+	credentialsFileRoot = dir
+}
+
+// resolveFileReferences replaces the file references of the credentials with
+// the content of the referenced files. The keytab is passed to the Terraform
+// provider as a path, so a referenced keytab is only checked to be readable
+// and its path is used as the value, with its encoding set to path so that it
+// is not mistaken for a base64 encoded keytab.
+func resolveFileReferences(creds map[string]string) error {
+	for _, key := range fileReferenceKeys {
+		value, ok := creds[key]
+		if !ok || !strings.HasPrefix(value, fileReferencePrefix) {
+			continue
+		}
+
+		f, path, err := openFileReference(key, strings.TrimPrefix(value, fileReferencePrefix))
+		if err != nil {
+			return err
+		}
+
+		if key == keyTab {
+			_ = f.Close()
+			creds[key] = path
+			creds[keyKeytabEncoding] = keytabEncodingPath
+			continue
+		}
+
+		data, err := io.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			return errors.Wrapf(err, errReadFileReference, key)
+		}
+		creds[key] = strings.TrimRight(string(data), "\r\n")
+	}
+	return nil
+}
+
+// openFileReference opens the file the given key references within
+// credentialsFileRoot and returns it with its path. Absolute names and names
+// escaping the root, including through symlinks, are rejected.
+func openFileReference(key, name string) (*os.File, string, error) {
+	if credentialsFileRoot == "" {
+		return nil, "", errors.Errorf(errFileReferencesDisabled, key)
+	}
+	name = filepath.Clean(name)
+	if !filepath.IsLocal(name) {
+		return nil, "", errors.Errorf(errFileReferenceNotLocal, key, name)
+	}
+
+	root, err := os.OpenRoot(credentialsFileRoot)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, errReadFileReference, key)
+	}
+	f, err := root.Open(name)
+	_ = root.Close()
+	if err != nil {
+		return nil, "", errors.Wrapf(err, errReadFileReference, key)
+	}
+	return f, filepath.Join(credentialsFileRoot, name), nil
+}
+
+// writeDigestFile writes the given data to a temporary file named after the
+// given prefix and the digest of the data, and returns its path. Reconciles
+// writing the same data reuse the same file instead of leaving a file behind
+// per reconcile.
+func writeDigestFile(prefix string, data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	path := filepath.Join(os.TempDir(), prefix+hex.EncodeToString(sum[:]))
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	return path, os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,150 @@
+package clients
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// withCredentialsFileRoot sets the credentials file root for the duration of
+// the test.
+func withCredentialsFileRoot(t *testing.T, dir string) {
+	t.Helper()
+	prev := credentialsFileRoot
+	SetCredentialsFileRoot(dir)
+	t.Cleanup(func() { SetCredentialsFileRoot(prev) })
+}
+
+func TestResolveFileReferences(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	if err := os.MkdirAll(filepath.Join(root, "tsig"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "password"), []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "tsig", "key"), []byte("c2VjcmV0\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "dns.keytab"), []byte{0x05, 0x02}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(dir, "outside")
+	if err := os.WriteFile(outside, []byte("outside\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		creds map[string]string
+		err   bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		root   string
+		creds  map[string]string
+		want   want
+	}{
+		"NoReferences": {
+			reason: "Credentials without file references should be unchanged, even without a root.",
+			creds:  map[string]string{keyPassword: "passw0rd"},
+			want:   want{creds: map[string]string{keyPassword: "passw0rd"}},
+		},
+		"Password": {
+			reason: "A referenced password should be read from the file without trailing newlines.",
+			root:   root,
+			creds:  map[string]string{keyPassword: fileReferencePrefix + "password"},
+			want:   want{creds: map[string]string{keyPassword: "s3cret"}},
+		},
+		"KeySecretInSubdirectory": {
+			reason: "A referenced key_secret should be read from a subdirectory of the root.",
+			root:   root,
+			creds:  map[string]string{transactionKeySecret: fileReferencePrefix + "tsig/key"},
+			want:   want{creds: map[string]string{transactionKeySecret: "c2VjcmV0"}},
+		},
+		"Keytab": {
+			reason: "A referenced keytab should be used as a path within the root, with its encoding set to path.",
+			root:   root,
+			creds:  map[string]string{keyTab: fileReferencePrefix + "./dns.keytab"},
+			want:   want{creds: map[string]string{keyTab: filepath.Join(root, "dns.keytab"), keyKeytabEncoding: keytabEncodingPath}},
+		},
+		"Disabled": {
+			reason: "File references should be rejected when no root is configured.",
+			creds:  map[string]string{keyPassword: fileReferencePrefix + filepath.Join(root, "password")},
+			want:   want{creds: map[string]string{keyPassword: fileReferencePrefix + filepath.Join(root, "password")}, err: true},
+		},
+		"Absolute": {
+			reason: "An absolute path should be rejected, even if it is within the root.",
+			root:   root,
+			creds:  map[string]string{keyPassword: fileReferencePrefix + filepath.Join(root, "password")},
+			want:   want{creds: map[string]string{keyPassword: fileReferencePrefix + filepath.Join(root, "password")}, err: true},
+		},
+		"Traversal": {
+			reason: "A path escaping the root with .. should be rejected.",
+			root:   root,
+			creds:  map[string]string{keyPassword: fileReferencePrefix + "../outside"},
+			want:   want{creds: map[string]string{keyPassword: fileReferencePrefix + "../outside"}, err: true},
+		},
+		"TraversalWithinPath": {
+			reason: "A path escaping the root with .. after a subdirectory should be rejected.",
+			root:   root,
+			creds:  map[string]string{transactionKeySecret: fileReferencePrefix + "tsig/../../outside"},
+			want:   want{creds: map[string]string{transactionKeySecret: fileReferencePrefix + "tsig/../../outside"}, err: true},
+		},
+		"TraversalKeytab": {
+			reason: "A keytab path escaping the root should be rejected rather than passed to the provider.",
+			root:   root,
+			creds:  map[string]string{keyTab: fileReferencePrefix + "../outside"},
+			want:   want{creds: map[string]string{keyTab: fileReferencePrefix + "../outside"}, err: true},
+		},
+		"Symlink": {
+			reason: "A symlink pointing outside of the root should be rejected.",
+			root:   root,
+			creds:  map[string]string{keyPassword: fileReferencePrefix + "link"},
+			want:   want{creds: map[string]string{keyPassword: fileReferencePrefix + "link"}, err: true},
+		},
+		"MissingPassword": {
+			reason: "A reference to a missing password file should fail.",
+			root:   root,
+			creds:  map[string]string{keyPassword: fileReferencePrefix + "missing"},
+			want:   want{creds: map[string]string{keyPassword: fileReferencePrefix + "missing"}, err: true},
+		},
+		"MissingKeySecret": {
+			reason: "A reference to a missing key_secret file should fail.",
+			root:   root,
+			creds:  map[string]string{transactionKeySecret: fileReferencePrefix + "missing"},
+			want:   want{creds: map[string]string{transactionKeySecret: fileReferencePrefix + "missing"}, err: true},
+		},
+		"MissingKeytab": {
+			reason: "A reference to a missing keytab file should fail.",
+			root:   root,
+			creds:  map[string]string{keyTab: fileReferencePrefix + "missing"},
+			want:   want{creds: map[string]string{keyTab: fileReferencePrefix + "missing"}, err: true},
+		},
+		"MissingRoot": {
+			reason: "A reference should fail when the root does not exist.",
+			root:   filepath.Join(dir, "missing"),
+			creds:  map[string]string{keyPassword: fileReferencePrefix + "password"},
+			want:   want{creds: map[string]string{keyPassword: fileReferencePrefix + "password"}, err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			withCredentialsFileRoot(t, tc.root)
+			err := resolveFileReferences(tc.creds)
+			if gotErr := err != nil; gotErr != tc.want.err {
+				t.Errorf("\n%s\nresolveFileReferences(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.creds, tc.creds); diff != "" {
+				t.Errorf("\n%s\nresolveFileReferences(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
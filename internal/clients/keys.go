@@ -0,0 +1,42 @@
+package clients
+
+import "sort"
+
+// credentialKeys are all the keys of the credentials JSON the provider reads.
+var credentialKeys = map[string]bool{
+	keyRFC:                  true,
+	keyServer:               true,
+	keyServers:              true,
+	keyPort:                 true,
+	keyTCPPort:              true,
+	keyUDPPort:              true,
+	keyRetries:              true,
+	keyTimeout:              true,
+	keyTransport:            true,
+	keyRealm:                true,
+	keyRealmPreserveCase:    true,
+	keyUsername:             true,
+	keyPassword:             true,
+	keyTab:                  true,
+	keyKeytabEncoding:       true,
+	keyKeytabSecretKey:      true,
+	keyKeytabPrincipalCheck: true,
+	keyKrb5Config:           true,
+	transactionKeyAlgorithm: true,
+	transcationKeyName:      true,
+	transactionKeySecret:    true,
+}
+
+// unknownCredentialKeys returns the keys of the given credentials the
+// provider does not read, in sorted order. They are usually misspelled keys,
+// whose values are otherwise silently ignored.
+func unknownCredentialKeys(creds map[string]string) []string {
+	var unknown []string
+	for k := range creds {
+		if !credentialKeys[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
@@ -0,0 +1,92 @@
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnknownCredentialKeys(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		creds  map[string]string
+		want   []string
+	}{
+		"Known": {
+			reason: "Credentials of known keys should have no unknown keys.",
+			creds:  map[string]string{keyRFC: keyBasedTransactionRFC, keyServer: "ns1.example.com", transcationKeyName: "tsig.", transactionKeyAlgorithm: "hmac-sha256", transactionKeySecret: "c2VjcmV0"},
+		},
+		"AllKnown": {
+			reason: "Every key the provider reads should be known.",
+			creds: map[string]string{
+				keyRFC: "", keyServer: "", keyServers: "", keyPort: "", keyTCPPort: "", keyUDPPort: "", keyRetries: "", keyTimeout: "", keyTransport: "",
+				keyRealm: "", keyRealmPreserveCase: "", keyUsername: "", keyPassword: "", keyTab: "", keyKeytabEncoding: "", keyKeytabSecretKey: "",
+				keyKeytabPrincipalCheck: "", keyKrb5Config: "", transactionKeyAlgorithm: "", transcationKeyName: "", transactionKeySecret: "",
+			},
+		},
+		"Misspelled": {
+			reason: "Misspelled keys should be reported in sorted order.",
+			creds:  map[string]string{keyServer: "ns1.example.com", "timout": "5s", "key_secert": "c2VjcmV0"},
+			want:   []string{"key_secert", "timout"},
+		},
+		"Case": {
+			reason: "Keys are case sensitive, so a key in another case should be reported.",
+			creds:  map[string]string{"Server": "ns1.example.com"},
+			want:   []string{"Server"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := unknownCredentialKeys(tc.creds)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nunknownCredentialKeys(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateCredentialsUnknownKeys(t *testing.T) {
+	type want struct {
+		unknown []string
+		err     bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		creds  string
+		want   want
+	}{
+		"Valid": {
+			reason: "Valid credentials should have no unknown keys.",
+			creds:  `{"rfc":"2845","server":"ns1.example.com","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"c2VjcmV0"}`,
+		},
+		"UnknownKeys": {
+			reason: "Unknown keys should be returned without failing the validation.",
+			creds:  `{"rfc":"2845","server":"ns1.example.com","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"c2VjcmV0","timout":"5s"}`,
+			want:   want{unknown: []string{"timout"}},
+		},
+		"InvalidWithUnknownKeys": {
+			reason: "Unknown keys should be returned along with the error of invalid credentials, as they are often its cause.",
+			creds:  `{"rfc":"2845","sever":"ns1.example.com","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"c2VjcmV0"}`,
+			want:   want{unknown: []string{"sever"}, err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pcSpec, err := ProviderConfigSpec(testProviderConfigWith(nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			unknown, err := ValidateCredentials(context.Background(), testClient(t, testSecret(tc.creds)), pcSpec)
+			if gotErr := err != nil; gotErr != tc.want.err {
+				t.Errorf("\n%s\nValidateCredentials(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			if diff := cmp.Diff(tc.want.unknown, unknown); diff != "" {
+				t.Errorf("\n%s\nValidateCredentials(...): -want unknown keys, +got unknown keys:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+const (
+	errKeytabPrincipalMismatch = "keytab %s does not contain a key for principal %s@%s"
+
+	// keyKeytabPrincipalCheck selects how a keytab principal mismatch is handled.
+	// A mismatch is logged by default and fails the setup when set to "strict".
+	keyKeytabPrincipalCheck    = "keytab_principal_check"
+	keytabPrincipalCheckStrict = "strict"
+
+	errDecodeKeytab          = "cannot decode the base64 encoded keytab"
+	errWriteKeytab           = "cannot write the decoded keytab"
+	errInvalidKeytabEncoding = "unsupported keytab_encoding %q, valid values are base64 or path"
+	errNotAKeytab            = "the base64 encoded keytab does not start with a keytab version header"
+
+	// keyKeytabEncoding tells whether the keytab credential holds a base64
+	// encoded keytab or a path. It is detected from the value when unset.
+	keyKeytabEncoding    = "keytab_encoding"
+	keytabEncodingBase64 = "base64"
+	keytabEncodingPath   = "path"
+
+	keytabFilePrefix = "dns-v2-keytab-"
+
+	// keytabFormat is the first byte of every keytab file, followed by the
+	// keytab format version, 1 or 2.
+	keytabFormat = 0x05
+
+	errKeytabSecretKeyNeedsSecret = "keytab_secret_key requires credentials with a Secret source"
+	errKeytabSecretKeyConflict    = "keytab_secret_key cannot be set together with keytab"
+	errKeytabSecretKeyNotFound    = "key %q referenced by keytab_secret_key is not set in the credentials Secret"
+
+	// keyKeytabSecretKey names another key of the credentials Secret holding
+	// the raw keytab.
+	keyKeytabSecretKey = "keytab_secret_key"
+)
+
+// checkKeytabPrincipal makes a best-effort check that the keytab referenced by
+// the credentials holds a key for username@REALM. Keytabs that cannot be read
+// or parsed are left for the provider to report.
+func checkKeytabPrincipal(creds map[string]string) error {
+	path := creds[keyTab]
+	if path == "" {
+		return nil
+	}
+
+	kt, err := keytab.Load(path)
+	if err != nil {
+		return nil
+	}
+
+	username, realm := bareUsername(creds[keyUsername]), creds[keyRealm]
+	for _, e := range kt.Entries {
+		if strings.Join(e.Principal.Components, "/") == username && strings.EqualFold(e.Principal.Realm, realm) {
+			return nil
+		}
+	}
+
+	return errors.Errorf(errKeytabPrincipalMismatch, path, username, strings.ToUpper(realm))
+}
+
+// materializeKeytab replaces a base64 encoded keytab in the credentials with
+// the path of a file holding the decoded keytab, since the provider only
+// accepts keytab paths. Without a keytab_encoding, absolute paths and paths of
+// existing files are kept, and any other value is only treated as an encoded
+// keytab when it decodes as standard base64 to a keytab; paths such as
+// /keytabs/dns are valid base64 too.
+func materializeKeytab(creds map[string]string) error {
+	value, ok := creds[keyTab]
+	if !ok || value == "" {
+		return nil
+	}
+
+	var data []byte
+	switch encoding := creds[keyKeytabEncoding]; encoding {
+	case keytabEncodingPath:
+		return nil
+	case keytabEncodingBase64:
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return errors.Wrap(err, errDecodeKeytab)
+		}
+		if !isKeytab(decoded) {
+			return errors.New(errNotAKeytab)
+		}
+		data = decoded
+	case "":
+		if strings.HasPrefix(value, "/") {
+			return nil
+		}
+		if _, err := os.Stat(value); err == nil {
+			return nil
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || !isKeytab(decoded) {
+			return nil
+		}
+		data = decoded
+	default:
+		return errors.Errorf(errInvalidKeytabEncoding, encoding)
+	}
+
+	path, err := writeKeytab(data)
+	if err != nil {
+		return err
+	}
+	creds[keyTab] = path
+	return nil
+}
+
+// isKeytab reports whether the given data starts with the header of a keytab
+// file of format version 1 or 2.
+func isKeytab(data []byte) bool {
+	return bytes.HasPrefix(data, []byte{keytabFormat, 0x01}) || bytes.HasPrefix(data, []byte{keytabFormat, 0x02})
+}
+
+// loadSecretKeytab sets the keytab of the credentials to the path of a file
+// holding the raw keytab stored under the keytab_secret_key of the
+// credentials Secret.
+func loadSecretKeytab(ctx context.Context, c client.Client, extract CredentialExtractor, pcSpec *namespacedv1beta1.ProviderConfigSpec, creds map[string]string) error {
+	key, ok := creds[keyKeytabSecretKey]
+	if !ok {
+		return nil
+	}
+	if _, ok := creds[keyTab]; ok {
+		return errors.New(errKeytabSecretKeyConflict)
+	}
+	ref := pcSpec.Credentials.SecretRef
+	if pcSpec.Credentials.Source != xpv1.CredentialsSourceSecret || ref == nil {
+		return errors.New(errKeytabSecretKeyNeedsSecret)
+	}
+
+	keytabRef := ref.DeepCopy()
+	keytabRef.Key = key
+	data, err := extractCredentialsWith(ctx, c, extract, pcSpec, xpv1.CommonCredentialSelectors{SecretRef: keytabRef})
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.Errorf(errKeytabSecretKeyNotFound, key)
+	}
+
+	path, err := writeKeytab(data)
+	if err != nil {
+		return err
+	}
+	creds[keyTab] = path
+	creds[keyKeytabEncoding] = keytabEncodingPath
+	return nil
+}
+
+// writeKeytab writes the given keytab to a file and returns its path.
+func writeKeytab(data []byte) (string, error) {
+	path, err := writeDigestFile(keytabFilePrefix, data)
+	return path, errors.Wrap(err, errWriteKeytab)
+}
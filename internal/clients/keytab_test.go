@@ -0,0 +1,111 @@
+package clients
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"testing"
+)
+
+func TestMaterializeKeytab(t *testing.T) {
+	// kt is the header of an empty keytab of format version 2.
+	kt := []byte{keytabFormat, 0x02}
+	encoded := base64.StdEncoding.EncodeToString(kt)
+
+	// existing is a relative path that is also valid base64 of a keytab
+	// header, so only the file existing tells it apart.
+	existing := "BQIA"
+	t.Chdir(t.TempDir())
+	// Decoded keytabs are written to the temporary directory.
+	t.Setenv("TMPDIR", t.TempDir())
+	if err := os.WriteFile(existing, kt, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		// decoded is the keytab expected in a written file, or nil when the
+		// keytab is expected to be unchanged.
+		decoded []byte
+		err     bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		creds  map[string]string
+		want   want
+	}{
+		"NoKeytab": {
+			reason: "Credentials without a keytab should be unchanged.",
+			creds:  map[string]string{},
+		},
+		"Path": {
+			reason: "A keytab with the path encoding should be used as a path.",
+			creds:  map[string]string{keyTab: encoded, keyKeytabEncoding: keytabEncodingPath},
+		},
+		"AbsolutePathValidBase64": {
+			reason: "An absolute path should be used as a path even though it is valid base64.",
+			creds:  map[string]string{keyTab: "/keytabs/dns"},
+		},
+		"ExistingRelativePath": {
+			reason: "The path of an existing file should be used as a path even though it decodes to a keytab.",
+			creds:  map[string]string{keyTab: existing},
+		},
+		"DetectedBase64": {
+			reason: "Valid base64 of a keytab should be decoded to a file.",
+			creds:  map[string]string{keyTab: encoded},
+			want:   want{decoded: kt},
+		},
+		"DetectedBase64NotAKeytab": {
+			reason: "Valid base64 of anything but a keytab should be used as a path.",
+			creds:  map[string]string{keyTab: "ZG5zLmtleXRhYg=="},
+		},
+		"Base64": {
+			reason: "A keytab with the base64 encoding should be decoded to a file.",
+			creds:  map[string]string{keyTab: encoded, keyKeytabEncoding: keytabEncodingBase64},
+			want:   want{decoded: kt},
+		},
+		"Base64Invalid": {
+			reason: "A keytab with the base64 encoding that is not valid base64 should fail.",
+			creds:  map[string]string{keyTab: "not base64", keyKeytabEncoding: keytabEncodingBase64},
+			want:   want{err: true},
+		},
+		"Base64NotAKeytab": {
+			reason: "A keytab with the base64 encoding that does not decode to a keytab should fail.",
+			creds:  map[string]string{keyTab: "ZG5zLmtleXRhYg==", keyKeytabEncoding: keytabEncodingBase64},
+			want:   want{err: true},
+		},
+		"UnsupportedEncoding": {
+			reason: "An unsupported keytab_encoding should fail.",
+			creds:  map[string]string{keyTab: encoded, keyKeytabEncoding: "hex"},
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			value := tc.creds[keyTab]
+			err := materializeKeytab(tc.creds)
+			if gotErr := err != nil; gotErr != tc.want.err {
+				t.Fatalf("\n%s\nmaterializeKeytab(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			if tc.want.err {
+				return
+			}
+
+			got := tc.creds[keyTab]
+			if tc.want.decoded == nil {
+				if got != value {
+					t.Errorf("\n%s\nmaterializeKeytab(...): want keytab %q unchanged, got %q", tc.reason, value, got)
+				}
+				return
+			}
+			data, err := os.ReadFile(got)
+			if err != nil {
+				t.Fatalf("\n%s\nmaterializeKeytab(...): cannot read the written keytab: %v", tc.reason, err)
+			}
+			if !bytes.Equal(data, tc.want.decoded) {
+				t.Errorf("\n%s\nmaterializeKeytab(...): want keytab %v written, got %v", tc.reason, tc.want.decoded, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package clients
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errReadKrb5Config  = "cannot read the krb5_config file"
+	errWriteKrb5Config = "cannot write the krb5_config"
+
+	// keyKrb5Config is either the path of a krb5.conf file or its inline
+	// content.
+	keyKrb5Config = "krb5_config"
+
+	// krb5ConfigEnv is the environment variable the Kerberos library of the
+	// provider reads the krb5.conf path from.
+	krb5ConfigEnv = "KRB5_CONFIG"
+
+	// keyRealmPreserveCase keeps the case of the realm when set to "true".
+	keyRealmPreserveCase = "realm_preserve_case"
+)
+
+var (
+	// krb5ConfigPath is the krb5.conf owned by the provider, which
+	// KRB5_CONFIG points at once any ProviderConfig sets a krb5_config.
+	krb5ConfigPath = filepath.Join(os.TempDir(), "dns-v2-krb5.conf")
+
+	// krb5ConfigMu serializes the writes of krb5ConfigPath.
+	krb5ConfigMu sync.Mutex
+)
+
+// applyKrb5Config points the Kerberos library at the krb5.conf set by the
+// krb5_config credential. Inline content, recognized by a line break or a
+// leading section header, and the content of a referenced file are written
+// to krb5ConfigPath, which is replaced whenever the configuration changes.
+// The Terraform provider runs in the provider process and reads KRB5_CONFIG
+// when authenticating, so the configuration is shared by all ProviderConfigs,
+// and the last one applied wins.
+func applyKrb5Config(creds map[string]string) error {
+	value := creds[keyKrb5Config]
+	if value == "" {
+		return nil
+	}
+
+	data := []byte(value)
+	if !strings.Contains(value, "\n") && !strings.HasPrefix(strings.TrimSpace(value), "[") {
+		d, err := os.ReadFile(value)
+		if err != nil {
+			return errors.Wrap(err, errReadKrb5Config)
+		}
+		data = d
+	}
+
+	krb5ConfigMu.Lock()
+	defer krb5ConfigMu.Unlock()
+	if current, err := os.ReadFile(krb5ConfigPath); err != nil || !bytes.Equal(current, data) {
+		if err := replaceFile(krb5ConfigPath, data); err != nil {
+			return errors.Wrap(err, errWriteKrb5Config)
+		}
+	}
+	return errors.Wrap(os.Setenv(krb5ConfigEnv, krb5ConfigPath), errWriteKrb5Config)
+}
+
+// replaceFile atomically replaces the content of the given file, so that a
+// concurrent reader sees either the previous or the new content.
+func replaceFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// normalizeRealm upper-cases the realm of the credentials, as Kerberos realms
+// conventionally are, unless realm_preserve_case is set. It reports whether
+// the realm was changed.
+func normalizeRealm(creds map[string]string) bool {
+	realm, ok := creds[keyRealm]
+	if !ok || creds[keyRealmPreserveCase] == "true" {
+		return false
+	}
+	creds[keyRealm] = strings.ToUpper(realm)
+	return creds[keyRealm] != realm
+}
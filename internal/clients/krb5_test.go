@@ -0,0 +1,119 @@
+package clients
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApplyKrb5Config(t *testing.T) {
+	dir := t.TempDir()
+	prev := krb5ConfigPath
+	krb5ConfigPath = filepath.Join(dir, "krb5.conf")
+	t.Cleanup(func() { krb5ConfigPath = prev })
+	t.Setenv(krb5ConfigEnv, "")
+
+	example := "[libdefaults]\n  default_realm = EXAMPLE.COM\n"
+	corp := "[libdefaults]\n  default_realm = CORP.EXAMPLE.COM\n"
+	file := filepath.Join(dir, "mounted.conf")
+	if err := os.WriteFile(file, []byte(corp), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct {
+		// config is the expected content of krb5ConfigPath, or empty when
+		// KRB5_CONFIG is expected to be unset.
+		config string
+		err    bool
+	}
+
+	// The cases run in order, as each applies on top of the configuration
+	// written by the previous ones.
+	cases := []struct {
+		name   string
+		reason string
+		value  string
+		want   want
+	}{
+		{
+			name:   "Unset",
+			reason: "Credentials without krb5_config should leave KRB5_CONFIG unset.",
+		},
+		{
+			name:   "Inline",
+			reason: "Inline content should be written to the provider owned krb5.conf.",
+			value:  example,
+			want:   want{config: example},
+		},
+		{
+			name:   "Same",
+			reason: "Applying the same content again should keep it.",
+			value:  example,
+			want:   want{config: example},
+		},
+		{
+			name:   "Changed",
+			reason: "A changed configuration should replace the previous one rather than be rejected.",
+			value:  corp,
+			want:   want{config: corp},
+		},
+		{
+			name:   "Path",
+			reason: "The content of a referenced file should be written to the provider owned krb5.conf.",
+			value:  file,
+			want:   want{config: corp},
+		},
+		{
+			name:   "SectionHeader",
+			reason: "Single line content starting with a section header should be treated as inline content.",
+			value:  "[libdefaults]",
+			want:   want{config: "[libdefaults]"},
+		},
+		{
+			name:   "MissingPath",
+			reason: "A reference to a missing file should fail and keep the previous configuration.",
+			value:  filepath.Join(dir, "missing.conf"),
+			want:   want{config: "[libdefaults]", err: true},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			creds := map[string]string{}
+			if tc.value != "" {
+				creds[keyKrb5Config] = tc.value
+			}
+			err := applyKrb5Config(creds)
+			if gotErr := err != nil; gotErr != tc.want.err {
+				t.Errorf("\n%s\napplyKrb5Config(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+
+			if tc.want.config == "" {
+				if got := os.Getenv(krb5ConfigEnv); got != "" {
+					t.Errorf("\n%s\napplyKrb5Config(...): want %s unset, got %q", tc.reason, krb5ConfigEnv, got)
+				}
+				return
+			}
+			if diff := cmp.Diff(krb5ConfigPath, os.Getenv(krb5ConfigEnv)); diff != "" {
+				t.Errorf("\n%s\napplyKrb5Config(...): -want %s, +got:\n%s", tc.reason, krb5ConfigEnv, diff)
+			}
+			got, err := os.ReadFile(krb5ConfigPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.config, string(got)); diff != "" {
+				t.Errorf("\n%s\napplyKrb5Config(...): -want config, +got config:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("applyKrb5Config(...): want no temporary files left behind, got %d entries", len(entries))
+	}
+}
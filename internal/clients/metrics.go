@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Outcomes of a Terraform setup, the values of the outcome label of the setup
+// metrics. The label is limited to these values so that its cardinality does
+// not grow with the number of ProviderConfigs or resources.
+const (
+	outcomeSuccess                  = "success"
+	outcomeProviderConfigFailure    = "provider-config-failure"
+	outcomeResourceFailure          = "resource-failure"
+	outcomeCredentialExtractFailure = "credential-extract-failure"
+	outcomeUnmarshalFailure         = "unmarshal-failure"
+	outcomeCredentialFailure        = "credential-failure"
+	outcomeMissingServer            = "missing-server"
+	outcomeAuthValidationFailure    = "auth-validation-failure"
+	outcomeProviderFailure          = "provider-failure"
+)
+
+// SetupMetrics are the metrics of the Terraform setups built for managed
+// resources. It is a prometheus.Collector, to be registered with the metrics
+// registry of the provider.
+type SetupMetrics struct {
+	total    *prometheus.CounterVec
+	duration prometheus.Histogram
+}
+
+// NewSetupMetrics returns the metrics of the Terraform setups.
+func NewSetupMetrics() *SetupMetrics {
+	return &SetupMetrics{
+		total: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "dns_v2",
+			Name:      "setup_total",
+			Help:      "The number of Terraform setups built for managed resources, by outcome.",
+		}, []string{"outcome"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Subsystem: "dns_v2",
+			Name:      "setup_duration_seconds",
+			Help:      "The time it took to build the Terraform setup of a managed resource.",
+			Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *SetupMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.total.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *SetupMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.total.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+// observe records a setup that ended with the given outcome after the given
+// duration. A nil SetupMetrics records nothing.
+func (m *SetupMetrics) observe(outcome string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.total.WithLabelValues(outcome).Inc()
+	m.duration.Observe(d.Seconds())
+}
@@ -0,0 +1,59 @@
+package clients
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+// previewKeytab stands in for a keytab read from keytab_secret_key, which
+// cannot be read without a cluster.
+const previewKeytab = "<keytab from the %s key of the credentials Secret>"
+
+// PreviewProviderConfig runs the given credentials JSON through the
+// validations and the build of the provider configuration the setup of a
+// managed resource performs, without a cluster. It returns the effective
+// provider block, rendered by ExportProviderConfig with secrets redacted, and
+// the unknown keys of the credentials. The spec may be nil; its connection
+// settings and explicitDefaults are applied like in the setup.
+func PreviewProviderConfig(data []byte, pcSpec *namespacedv1beta1.ProviderConfigSpec) (string, []string, error) {
+	if pcSpec == nil {
+		pcSpec = &namespacedv1beta1.ProviderConfigSpec{}
+	}
+
+	creds, err := unmarshalCredentials(pcSpec, data)
+	if err != nil {
+		return "", nil, err
+	}
+	unknown := unknownCredentialKeys(creds)
+
+	if err := resolveFileReferences(creds); err != nil {
+		return "", unknown, err
+	}
+
+	if creds[keyRFC] == gsstsigRFC {
+		if err := applyKrb5Config(creds); err != nil {
+			return "", unknown, err
+		}
+		normalizeRealm(creds)
+		if key, ok := creds[keyKeytabSecretKey]; ok {
+			if _, ok := creds[keyTab]; ok {
+				return "", unknown, errors.New(errKeytabSecretKeyConflict)
+			}
+			creds[keyTab] = fmt.Sprintf(previewKeytab, key)
+			creds[keyKeytabEncoding] = keytabEncodingPath
+		}
+		if err := materializeKeytab(creds); err != nil {
+			return "", unknown, redactError(err, creds)
+		}
+	}
+
+	authConfig, err := buildAuthConfig(creds, pcSpec.ExplicitDefaults, logr.Discard())
+	if err != nil {
+		return "", unknown, redactError(errors.Wrap(err, errBuildAuthConfig), creds)
+	}
+	return ExportProviderConfig(map[string]any{update: []any{authConfig}}), unknown, nil
+}
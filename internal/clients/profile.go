@@ -0,0 +1,63 @@
+package clients
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+const errUnknownDefaultProfile = "defaultProfile %q does not match any profile"
+
+// selectProfile returns the profile of the given ProviderConfig spec matching
+// the given zone by the longest zone suffix, falling back to the default
+// profile. It returns nil when no profile applies.
+func selectProfile(pcSpec *namespacedv1beta1.ProviderConfigSpec, zone string) (*namespacedv1beta1.ZoneProfile, error) {
+	zone = canonicalZone(zone)
+
+	var selected *namespacedv1beta1.ZoneProfile
+	longest := -1
+	for i, p := range pcSpec.Profiles {
+		for _, suffix := range p.Zones {
+			suffix = canonicalZone(suffix)
+			if (zone == suffix || strings.HasSuffix(zone, "."+suffix)) && len(suffix) > longest {
+				selected, longest = &pcSpec.Profiles[i], len(suffix)
+			}
+		}
+	}
+	if selected != nil || pcSpec.DefaultProfile == "" {
+		return selected, nil
+	}
+
+	for i, p := range pcSpec.Profiles {
+		if p.Name == pcSpec.DefaultProfile {
+			return &pcSpec.Profiles[i], nil
+		}
+	}
+	return nil, errors.Errorf(errUnknownDefaultProfile, pcSpec.DefaultProfile)
+}
+
+// applyProfile overrides the retries, timeout and transport of the
+// credentials with the ones set by the given profile.
+func applyProfile(p *namespacedv1beta1.ZoneProfile, creds map[string]string) {
+	if p == nil {
+		return
+	}
+	if p.Retries != nil {
+		creds[keyRetries] = strconv.Itoa(*p.Retries)
+	}
+	if p.Timeout != "" {
+		creds[keyTimeout] = p.Timeout
+	}
+	if p.Transport != "" {
+		creds[keyTransport] = p.Transport
+	}
+}
+
+// canonicalZone returns the given zone name in lower case without a trailing
+// dot.
+func canonicalZone(zone string) string {
+	return strings.ToLower(strings.TrimSuffix(zone, "."))
+}
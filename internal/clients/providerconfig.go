@@ -0,0 +1,91 @@
+package clients
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1beta1 "github.com/dana-team/provider-dns-v2/apis/cluster/v1beta1"
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+const errUnknownProviderConfigType = "unknown provider config type"
+
+// ProviderConfigSpec returns the shared spec of the given ProviderConfig or
+// ClusterProviderConfig of either scope. The secret references of a
+// namespaced ProviderConfig are resolved in its own namespace.
+func ProviderConfigSpec(pc client.Object) (*namespacedv1beta1.ProviderConfigSpec, error) {
+	switch pc := pc.(type) {
+	case *clusterv1beta1.ProviderConfig:
+		return toSharedPCSpec(pc), nil
+	case *namespacedv1beta1.ProviderConfig:
+		pcSpec := pc.Spec.DeepCopy()
+		if pcSpec.Credentials.SecretRef != nil {
+			pcSpec.Credentials.SecretRef.Namespace = pc.GetNamespace()
+		}
+		if pcSpec.Credentials.SecretClusterRef != nil {
+			pcSpec.Credentials.SecretClusterRef.Namespace = pc.GetNamespace()
+		}
+		return pcSpec, nil
+	case *namespacedv1beta1.ClusterProviderConfig:
+		pcSpec := pc.Spec.DeepCopy()
+		if pcSpec.Credentials.SecretRef != nil && pcSpec.Credentials.SecretRef.Namespace == "" {
+			return nil, errors.New(errClusterConfigNeedsSecretNamespace)
+		}
+		if pcSpec.Credentials.SecretClusterRef != nil && pcSpec.Credentials.SecretClusterRef.Namespace == "" {
+			return nil, errors.New(errClusterConfigNeedsSecretNamespace)
+		}
+		return pcSpec, nil
+	default:
+		return nil, errors.New(errUnknownProviderConfigType)
+	}
+}
+
+// ValidateCredentials extracts the credentials of the given ProviderConfig
+// spec and checks that they build a valid provider configuration. It returns
+// the keys of the credentials the provider does not read, which are ignored.
+func ValidateCredentials(ctx context.Context, c client.Client, pcSpec *namespacedv1beta1.ProviderConfigSpec) ([]string, error) {
+	data, err := extractCredentials(ctx, c, resource.CommonCredentialExtractor, pcSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := unmarshalCredentials(pcSpec, data)
+	if err != nil {
+		return nil, err
+	}
+	unknown := unknownCredentialKeys(creds)
+
+	if err := resolveFileReferences(creds); err != nil {
+		return unknown, err
+	}
+
+	if creds[keyRFC] == gsstsigRFC {
+		if err := loadSecretKeytab(ctx, c, resource.CommonCredentialExtractor, pcSpec, creds); err != nil {
+			return unknown, err
+		}
+		if err := materializeKeytab(creds); err != nil {
+			return unknown, redactError(err, creds)
+		}
+	}
+
+	_, err = buildAuthConfig(creds, pcSpec.ExplicitDefaults, logr.Discard())
+	return unknown, redactError(err, creds)
+}
+
+// BuildAuthConfig builds the update block of the Terraform provider
+// configuration from the given credentials, as the provider does for every
+// managed resource, so that tools can validate credentials offline. Errors
+// are the ones the provider reports, with secret values redacted. The
+// credentials must not use file: references or keytab_secret_key, which are
+// resolved before the configuration is built.
+func BuildAuthConfig(creds map[string]string) (map[string]any, error) {
+	config, err := buildAuthConfig(creds, false, logr.Discard())
+	if err != nil {
+		return nil, redactError(errors.Wrap(err, errBuildAuthConfig), creds)
+	}
+	return config, nil
+}
@@ -0,0 +1,153 @@
+package clients
+
+import (
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	upjetresource "github.com/crossplane/upjet/v2/pkg/resource"
+	"github.com/pkg/errors"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+const (
+	errGetParameters     = "cannot get the parameters of the managed resource"
+	errRecordSetTooLarge = "record set has %d values, exceeding the maxRecordSetSize of %d"
+
+	// defaultMaxRecordSetSize is used when the ProviderConfig does not set
+	// maxRecordSetSize.
+	defaultMaxRecordSetSize = 1000
+
+	// record parameters
+	paramZone = "zone"
+	paramName = "name"
+
+	// sizes of the fixed parts of a DNS UPDATE message, see RFC 2136.
+	messageHeaderSize  = 12
+	questionFixedSize  = 4
+	resourceFixedSize  = 10
+	domainNameOverhead = 2
+)
+
+// recordSetFields are the parameters holding the values of the record set
+// resources.
+var recordSetFields = []string{"addresses", "nameservers", "txt", "mx", "srv"}
+
+// recordValueFields are the parameters holding the value of the single
+// record resources.
+var recordValueFields = []string{"cname", "ptr"}
+
+// parameters returns the Terraform parameters of the given managed resource.
+// It returns nil for resources that are not Terraformed.
+func parameters(mg resource.Managed) (map[string]any, error) {
+	tr, ok := mg.(upjetresource.Terraformed)
+	if !ok {
+		return nil, nil
+	}
+	params, err := tr.GetParameters()
+	return params, errors.Wrap(err, errGetParameters)
+}
+
+// sendsUpdates reports whether a reconcile of the given managed resource may
+// create or update its record. Records being deleted, and records whose
+// management policies allow neither, are only observed or deleted, which must
+// keep working even when their parameters no longer pass the checks applied
+// before an update is sent.
+func sendsUpdates(mg resource.Managed) bool {
+	if mg.GetDeletionTimestamp() != nil {
+		return false
+	}
+	policies := mg.GetManagementPolicies()
+	if len(policies) == 0 {
+		return true
+	}
+	for _, p := range policies {
+		switch p {
+		case xpv1.ManagementActionAll, xpv1.ManagementActionCreate, xpv1.ManagementActionUpdate:
+			return true
+		}
+	}
+	return false
+}
+
+// checkRecordSetSize rejects record sets holding more values than allowed by
+// the ProviderConfig. Resources that are not record sets are ignored.
+func checkRecordSetSize(pcSpec *namespacedv1beta1.ProviderConfigSpec, params map[string]any) error {
+	limit := defaultMaxRecordSetSize
+	if pcSpec.MaxRecordSetSize != nil {
+		limit = *pcSpec.MaxRecordSetSize
+	}
+	for _, field := range recordSetFields {
+		values, ok := params[field].([]any)
+		if ok && len(values) > limit {
+			return errors.Errorf(errRecordSetTooLarge, len(values), limit)
+		}
+	}
+	return nil
+}
+
+// applyTCPFallback switches the transport of the credentials to TCP when the
+// estimated size of the update for the given parameters exceeds the
+// tcpFallbackThreshold of the ProviderConfig. The address family of the
+// configured transport is preserved.
+func applyTCPFallback(pcSpec *namespacedv1beta1.ProviderConfigSpec, params map[string]any, creds map[string]string) bool {
+	if pcSpec.TCPFallbackThreshold == nil || estimateUpdateSize(params) <= *pcSpec.TCPFallbackThreshold {
+		return false
+	}
+	transport := creds[keyTransport]
+	if transport == "" {
+		transport = defaultTransport
+	}
+	if strings.HasPrefix(transport, tcpTransport) {
+		return false
+	}
+	creds[keyTransport] = tcpTransport + strings.TrimPrefix(transport, defaultTransport)
+	return true
+}
+
+// estimateUpdateSize estimates the size in bytes of the DNS UPDATE message
+// adding the records described by the given parameters. The record data is
+// approximated by the length of its textual values, which is an upper bound
+// for most record types.
+func estimateUpdateSize(params map[string]any) int {
+	zone, _ := params[paramZone].(string)
+	name, _ := params[paramName].(string)
+	owner := len(zone) + domainNameOverhead
+	if name != "" {
+		owner += len(name) + 1
+	}
+
+	size := messageHeaderSize + len(zone) + domainNameOverhead + questionFixedSize
+	for _, field := range append(recordSetFields, recordValueFields...) {
+		var values []any
+		switch v := params[field].(type) {
+		case []any:
+			values = v
+		case string:
+			values = []any{v}
+		}
+		for _, value := range values {
+			size += owner + resourceFixedSize + valueSize(value)
+		}
+	}
+	return size
+}
+
+// valueSize returns the approximate wire size of a single record value.
+func valueSize(value any) int {
+	switch v := value.(type) {
+	case string:
+		return len(v) + 1
+	case map[string]any:
+		size := 0
+		for _, field := range v {
+			size += valueSize(field)
+		}
+		return size
+	default:
+		// numeric fields such as the MX preference and SRV priority,
+		// weight and port are encoded as 16-bit integers.
+		return 2
+	}
+}
@@ -0,0 +1,121 @@
+package clients
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1"
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+func TestCheckRecordSetSize(t *testing.T) {
+	values := func(n int) []any {
+		v := make([]any, n)
+		for i := range v {
+			v[i] = "192.0.2.1"
+		}
+		return v
+	}
+	limit := func(n int) *int { return &n }
+
+	cases := map[string]struct {
+		reason string
+		pcSpec *namespacedv1beta1.ProviderConfigSpec
+		params map[string]any
+		want   error
+	}{
+		"AtDefaultLimit": {
+			reason: "A record set with as many values as the default limit should be allowed.",
+			pcSpec: &namespacedv1beta1.ProviderConfigSpec{},
+			params: map[string]any{"addresses": values(defaultMaxRecordSetSize)},
+		},
+		"AboveDefaultLimit": {
+			reason: "A record set with more values than the default limit should be rejected.",
+			pcSpec: &namespacedv1beta1.ProviderConfigSpec{},
+			params: map[string]any{"addresses": values(defaultMaxRecordSetSize + 1)},
+			want:   errors.Errorf(errRecordSetTooLarge, defaultMaxRecordSetSize+1, defaultMaxRecordSetSize),
+		},
+		"AtConfiguredLimit": {
+			reason: "A record set with as many values as maxRecordSetSize should be allowed.",
+			pcSpec: &namespacedv1beta1.ProviderConfigSpec{MaxRecordSetSize: limit(2)},
+			params: map[string]any{"txt": values(2)},
+		},
+		"AboveConfiguredLimit": {
+			reason: "A record set with more values than maxRecordSetSize should be rejected.",
+			pcSpec: &namespacedv1beta1.ProviderConfigSpec{MaxRecordSetSize: limit(2)},
+			params: map[string]any{"nameservers": values(3)},
+			want:   errors.Errorf(errRecordSetTooLarge, 3, 2),
+		},
+		"NotARecordSet": {
+			reason: "Resources without record set values should be ignored.",
+			pcSpec: &namespacedv1beta1.ProviderConfigSpec{MaxRecordSetSize: limit(0)},
+			params: map[string]any{"cname": "target.example.com."},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := checkRecordSetSize(tc.pcSpec, tc.params)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncheckRecordSetSize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSendsUpdates(t *testing.T) {
+	now := metav1.Now()
+
+	cases := map[string]struct {
+		reason            string
+		deletionTimestamp *metav1.Time
+		policies          xpv1.ManagementPolicies
+		want              bool
+	}{
+		"DefaultPolicies": {
+			reason: "A resource with the default management policies may be updated.",
+			want:   true,
+		},
+		"All": {
+			reason:   "A resource with the All management policy may be updated.",
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionAll},
+			want:     true,
+		},
+		"ObserveOnly": {
+			reason:   "An observe-only resource is never updated.",
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve},
+			want:     false,
+		},
+		"ObserveAndDelete": {
+			reason:   "A resource that may only be observed and deleted is never updated.",
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionDelete},
+			want:     false,
+		},
+		"ObserveAndCreate": {
+			reason:   "A resource that may be created sends updates.",
+			policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve, xpv1.ManagementActionCreate},
+			want:     true,
+		},
+		"Deleting": {
+			reason:            "A resource being deleted is never updated.",
+			deletionTimestamp: &now,
+			want:              false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &v1alpha1.ARecordSet{}
+			mg.SetDeletionTimestamp(tc.deletionTimestamp)
+			mg.SetManagementPolicies(tc.policies)
+			if diff := cmp.Diff(tc.want, sendsUpdates(mg)); diff != "" {
+				t.Errorf("\n%s\nsendsUpdates(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+const (
+	errGetKubeconfig            = "cannot get kubeconfig referenced by secretClusterRef"
+	errParseKubeconfig          = "cannot parse kubeconfig referenced by secretClusterRef"
+	errUnsafeUser               = "user %q of the kubeconfig referenced by secretClusterRef sets %s, which is not allowed as it runs commands or reads files in the provider"
+	errUnsafeCluster            = "cluster %q of the kubeconfig referenced by secretClusterRef sets %s, which is not allowed as it reads files in the provider, inline it instead"
+	errNewRemoteClient          = "cannot create client for the cluster referenced by secretClusterRef"
+	errExtractRemoteCredentials = "cannot extract credentials from the cluster referenced by secretClusterRef"
+
+	// remoteClusterTimeout bounds requests to the cluster referenced by
+	// secretClusterRef so that an unreachable cluster fails setup instead of
+	// stalling the reconcile.
+	remoteClusterTimeout = 30 * time.Second
+
+	errCredentialsNotFound = "credentials Secret not found, it may not have been created yet"
+	errCredentialsRotating = "credentials file changed while it was read, it may be being rotated"
+
+	errCredentialsNotConfigured = "providerConfig credentials are not configured"
+	errNoCredentialsSource      = "credentials.source is not set"
+	errMissingSelector          = "credentials.source %s requires credentials.%s"
+)
+
+// A CredentialsNotFoundError is returned when the Secret holding the
+// credentials does not exist. It is usually transient, for example when a
+// ProviderConfig and its Secret are created together, and clears once the
+// Secret is created.
+type CredentialsNotFoundError struct {
+	err error
+}
+
+func (e *CredentialsNotFoundError) Error() string {
+	return errCredentialsNotFound + ": " + e.err.Error()
+}
+
+// Unwrap returns the error of the failed Secret read.
+func (e *CredentialsNotFoundError) Unwrap() error {
+	return e.err
+}
+
+// IsCredentialsNotFound reports whether the given error, or an error it wraps,
+// is a CredentialsNotFoundError.
+func IsCredentialsNotFound(err error) bool {
+	var e *CredentialsNotFoundError
+	return errors.As(err, &e)
+}
+
+// A CredentialsRotatingError is returned when the credentials file of the
+// Filesystem source changes while it is read, for example when it is rewritten
+// in place on rotation. It is transient and clears once the file is written.
+type CredentialsRotatingError struct{}
+
+func (e *CredentialsRotatingError) Error() string {
+	return errCredentialsRotating
+}
+
+// IsCredentialsRotating reports whether the given error, or an error it wraps,
+// is a CredentialsRotatingError.
+func IsCredentialsRotating(err error) bool {
+	var e *CredentialsRotatingError
+	return errors.As(err, &e)
+}
+
+// credentialsNotFound returns a CredentialsNotFoundError wrapping the given
+// error when it is a not found error of the API server, and the error
+// unchanged otherwise.
+func credentialsNotFound(err error) error {
+	if err != nil && kerrors.IsNotFound(err) {
+		return &CredentialsNotFoundError{err: err}
+	}
+	return err
+}
+
+// A CredentialExtractor extracts credentials from the given source using the
+// given selectors. resource.CommonCredentialExtractor, which reads them from
+// Secrets, environment variables or files, is used unless another one is set
+// with WithCredentialExtractor.
+type CredentialExtractor func(ctx context.Context, source xpv1.CredentialsSource, c client.Client, selectors xpv1.CommonCredentialSelectors) ([]byte, error)
+
+// extractCredentials extracts the credentials of the given ProviderConfig spec.
+// When a secretClusterRef is set the credentials Secret is read from the
+// cluster of the referenced kubeconfig rather than the local cluster.
+func extractCredentials(ctx context.Context, c client.Client, extract CredentialExtractor, pcSpec *namespacedv1beta1.ProviderConfigSpec) ([]byte, error) {
+	if err := checkCredentialsConfigured(pcSpec.Credentials); err != nil {
+		return nil, errors.Wrap(err, errCredentialsNotConfigured)
+	}
+	return extractCredentialsWith(ctx, c, extract, pcSpec, pcSpec.Credentials.CommonCredentialSelectors)
+}
+
+// checkCredentialsConfigured checks that the given credentials set a source
+// and the selector that source reads from, which the common extractor would
+// otherwise report with a less helpful error.
+func checkCredentialsConfigured(creds namespacedv1beta1.ProviderCredentials) error {
+	switch creds.Source {
+	case "":
+		return errors.New(errNoCredentialsSource)
+	case xpv1.CredentialsSourceSecret:
+		if creds.SecretRef == nil {
+			return errors.Errorf(errMissingSelector, creds.Source, "secretRef")
+		}
+	case xpv1.CredentialsSourceEnvironment:
+		if creds.Env == nil {
+			return errors.Errorf(errMissingSelector, creds.Source, "env")
+		}
+	case xpv1.CredentialsSourceFilesystem:
+		if creds.Fs == nil {
+			return errors.Errorf(errMissingSelector, creds.Source, "fs")
+		}
+	}
+	return nil
+}
+
+// extractCredentialsWith extracts credentials from the source of the given
+// ProviderConfig spec using the given selectors, honouring its
+// secretClusterRef.
+func extractCredentialsWith(ctx context.Context, c client.Client, extract CredentialExtractor, pcSpec *namespacedv1beta1.ProviderConfigSpec, selectors xpv1.CommonCredentialSelectors) ([]byte, error) {
+	ref := pcSpec.Credentials.SecretClusterRef
+	if ref == nil {
+		data, err := extract(ctx, pcSpec.Credentials.Source, c, selectors)
+		if err == nil && pcSpec.Credentials.Source == xpv1.CredentialsSourceFilesystem {
+			err = checkFileUnchanged(ctx, c, extract, selectors, data)
+		}
+		return data, errors.Wrap(credentialsNotFound(err), errExtractCredentials)
+	}
+
+	remote, err := remoteClient(ctx, c, extract, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := extract(ctx, pcSpec.Credentials.Source, remote, selectors)
+	return data, errors.Wrap(credentialsNotFound(err), errExtractRemoteCredentials)
+}
+
+// checkFileUnchanged reads the credentials file again and checks that its
+// content is the given data. Files projected from Secrets or by CSI drivers
+// are swapped atomically, but a file rewritten in place may be read while only
+// partially written, which would otherwise be reported as invalid credentials.
+func checkFileUnchanged(ctx context.Context, c client.Client, extract CredentialExtractor, selectors xpv1.CommonCredentialSelectors, data []byte) error {
+	again, err := extract(ctx, xpv1.CredentialsSourceFilesystem, c, selectors)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, again) {
+		return &CredentialsRotatingError{}
+	}
+	return nil
+}
+
+// remoteClient returns a client for the cluster of the kubeconfig stored in the
+// referenced Secret key.
+func remoteClient(ctx context.Context, c client.Client, extract CredentialExtractor, ref *xpv1.SecretKeySelector) (client.Client, error) {
+	kubeconfig, err := extract(ctx, xpv1.CredentialsSourceSecret, c, xpv1.CommonCredentialSelectors{SecretRef: ref})
+	if err != nil {
+		return nil, errors.Wrap(err, errGetKubeconfig)
+	}
+
+	cfg, err := remoteRESTConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := client.New(cfg, client.Options{})
+	return remote, errors.Wrap(err, errNewRemoteClient)
+}
+
+// remoteRESTConfig returns the REST config of the given kubeconfig. The
+// kubeconfig is read from a Secret that the author of a namespaced
+// ProviderConfig controls, so it may not hold anything that runs commands or
+// reads files in the provider pod: exec and auth-provider plugins, and token,
+// certificate or key files, are rejected. Their data must be inlined instead.
+func remoteRESTConfig(kubeconfig []byte) (*rest.Config, error) {
+	raw, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseKubeconfig)
+	}
+	if err := checkKubeconfig(raw); err != nil {
+		return nil, err
+	}
+
+	cfg, err := clientcmd.NewDefaultClientConfig(*raw, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, errParseKubeconfig)
+	}
+	cfg.Timeout = remoteClusterTimeout
+	return cfg, nil
+}
+
+// checkKubeconfig rejects a kubeconfig whose users or clusters run commands or
+// read files.
+func checkKubeconfig(cfg *clientcmdapi.Config) error {
+	for name, user := range cfg.AuthInfos {
+		switch {
+		case user.Exec != nil:
+			return errors.Errorf(errUnsafeUser, name, "exec")
+		case user.AuthProvider != nil:
+			return errors.Errorf(errUnsafeUser, name, "auth-provider")
+		case user.TokenFile != "":
+			return errors.Errorf(errUnsafeUser, name, "tokenFile")
+		case user.ClientCertificate != "":
+			return errors.Errorf(errUnsafeUser, name, "client-certificate")
+		case user.ClientKey != "":
+			return errors.Errorf(errUnsafeUser, name, "client-key")
+		}
+	}
+	for name, cluster := range cfg.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return errors.Errorf(errUnsafeCluster, name, "certificate-authority")
+		}
+	}
+	return nil
+}
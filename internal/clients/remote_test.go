@@ -0,0 +1,372 @@
+package clients
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+// kubeconfig returns a kubeconfig of a single cluster and user, whose user
+// stanza is the given YAML.
+func kubeconfig(cluster, user string) []byte {
+	return []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: remote
+  cluster:
+    server: https://remote.example.com:6443
+` + cluster + `
+users:
+- name: remote
+  user:
+` + user + `
+contexts:
+- name: remote
+  context:
+    cluster: remote
+    user: remote
+current-context: remote
+`)
+}
+
+func TestRemoteRESTConfig(t *testing.T) {
+	type want struct {
+		host  string
+		token string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason     string
+		kubeconfig []byte
+		want       want
+	}{
+		"InlineToken": {
+			reason:     "A kubeconfig with inline credentials should be accepted.",
+			kubeconfig: kubeconfig("    insecure-skip-tls-verify: true", "    token: t0ken"),
+			want:       want{host: "https://remote.example.com:6443", token: "t0ken"},
+		},
+		"Exec": {
+			reason:     "A kubeconfig running an exec plugin should be rejected.",
+			kubeconfig: kubeconfig("", "    exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: /bin/sh\n      args: [\"-c\", \"cat /var/run/secrets/kubernetes.io/serviceaccount/token\"]\n      interactiveMode: Never"),
+			want:       want{err: errors.Errorf(errUnsafeUser, "remote", "exec")},
+		},
+		"AuthProvider": {
+			reason:     "A kubeconfig using an auth-provider should be rejected.",
+			kubeconfig: kubeconfig("", "    auth-provider:\n      name: oidc"),
+			want:       want{err: errors.Errorf(errUnsafeUser, "remote", "auth-provider")},
+		},
+		"TokenFile": {
+			reason:     "A kubeconfig reading its token from a file should be rejected.",
+			kubeconfig: kubeconfig("", "    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token"),
+			want:       want{err: errors.Errorf(errUnsafeUser, "remote", "tokenFile")},
+		},
+		"ClientCertificateFile": {
+			reason:     "A kubeconfig reading its client certificate from a file should be rejected.",
+			kubeconfig: kubeconfig("", "    client-certificate: /etc/remote/tls.crt\n    client-key-data: a2V5"),
+			want:       want{err: errors.Errorf(errUnsafeUser, "remote", "client-certificate")},
+		},
+		"ClientKeyFile": {
+			reason:     "A kubeconfig reading its client key from a file should be rejected.",
+			kubeconfig: kubeconfig("", "    client-certificate-data: Y2VydA==\n    client-key: /etc/remote/tls.key"),
+			want:       want{err: errors.Errorf(errUnsafeUser, "remote", "client-key")},
+		},
+		"CertificateAuthorityFile": {
+			reason:     "A kubeconfig reading its certificate authority from a file should be rejected.",
+			kubeconfig: kubeconfig("    certificate-authority: /etc/remote/ca.crt", "    token: t0ken"),
+			want:       want{err: errors.Errorf(errUnsafeCluster, "remote", "certificate-authority")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := remoteRESTConfig(tc.kubeconfig)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nremoteRESTConfig(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.host, cfg.Host); diff != "" {
+				t.Errorf("\n%s\nremoteRESTConfig(...): -want host, +got host:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.token, cfg.BearerToken); diff != "" {
+				t.Errorf("\n%s\nremoteRESTConfig(...): -want token, +got token:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(remoteClusterTimeout, cfg.Timeout); diff != "" {
+				t.Errorf("\n%s\nremoteRESTConfig(...): -want timeout, +got timeout:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExtractCredentialsRejectsExecKubeconfig(t *testing.T) {
+	exec := kubeconfig("", "    exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: /bin/sh\n      interactiveMode: Never")
+
+	var extracted []string
+	extract := func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, s xpv1.CommonCredentialSelectors) ([]byte, error) {
+		extracted = append(extracted, s.SecretRef.Key)
+		return exec, nil
+	}
+	pcSpec := &namespacedv1beta1.ProviderConfigSpec{Credentials: namespacedv1beta1.ProviderCredentials{
+		Source:                    xpv1.CredentialsSourceSecret,
+		CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{Key: "credentials"}},
+		SecretClusterRef:          &xpv1.SecretKeySelector{Key: "kubeconfig"},
+	}}
+
+	_, err := extractCredentials(context.Background(), nil, extract, pcSpec)
+	if err == nil || !strings.Contains(err.Error(), "exec") {
+		t.Errorf("extractCredentials(...): want the exec kubeconfig to be rejected, got %v", err)
+	}
+	if diff := cmp.Diff([]string{"kubeconfig"}, extracted); diff != "" {
+		t.Errorf("extractCredentials(...): the credentials should not be read from the remote cluster: -want, +got:\n%s", diff)
+	}
+}
+
+func TestTerraformSetupCredentialsNotFound(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason  string
+		extract CredentialExtractor
+		objs    []client.Object
+		want    bool
+	}{
+		"SecretNotFound": {
+			reason:  "A missing credentials Secret should return a CredentialsNotFoundError.",
+			extract: resource.CommonCredentialExtractor,
+			objs:    []client.Object{testProviderConfigWith(nil)},
+			want:    true,
+		},
+		"SecretFound": {
+			reason:  "An existing credentials Secret should not return a CredentialsNotFoundError.",
+			extract: resource.CommonCredentialExtractor,
+			objs:    []client.Object{testProviderConfigWith(nil), testSecret(`{"server":"ns1.example.com"}`)},
+		},
+		"ExtractFailed": {
+			reason: "Other extraction errors should not return a CredentialsNotFoundError.",
+			extract: func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+				return nil, errBoom
+			},
+			objs: []client.Object{testProviderConfigWith(nil), testSecret(`{"server":"ns1.example.com"}`)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			setup := TerraformSetupBuilder("1.5.7", "hashicorp/dns", "3.4.0", WithCredentialExtractor(tc.extract))
+			_, err := setup(context.Background(), testClient(t, tc.objs...), testRecordSet())
+
+			var nf *CredentialsNotFoundError
+			if got := errors.As(err, &nf); got != tc.want {
+				t.Errorf("\n%s\nsetup(...): want a CredentialsNotFoundError %t, got %v", tc.reason, tc.want, err)
+			}
+			if got := IsCredentialsNotFound(err); got != tc.want {
+				t.Errorf("\n%s\nIsCredentialsNotFound(...): -want %t, +got %t", tc.reason, tc.want, got)
+			}
+			if tc.want && !kerrors.IsNotFound(nf.Unwrap()) {
+				t.Errorf("\n%s\nsetup(...): want the CredentialsNotFoundError to wrap the not found error, got %v", tc.reason, nf.Unwrap())
+			}
+		})
+	}
+}
+
+func TestCheckCredentialsConfigured(t *testing.T) {
+	secretRef := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: testSecretName, Namespace: testNamespace}, Key: testSecretKey}
+
+	cases := map[string]struct {
+		reason string
+		creds  namespacedv1beta1.ProviderCredentials
+		want   error
+	}{
+		"Empty": {
+			reason: "Credentials without a source should be rejected.",
+			want:   errors.New(errNoCredentialsSource),
+		},
+		"SecretWithoutSecretRef": {
+			reason: "Credentials of the Secret source without a secretRef should be rejected.",
+			creds:  namespacedv1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret},
+			want:   errors.Errorf(errMissingSelector, xpv1.CredentialsSourceSecret, "secretRef"),
+		},
+		"EnvironmentWithoutEnv": {
+			reason: "Credentials of the Environment source without an env should be rejected.",
+			creds:  namespacedv1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceEnvironment},
+			want:   errors.Errorf(errMissingSelector, xpv1.CredentialsSourceEnvironment, "env"),
+		},
+		"FilesystemWithoutFs": {
+			reason: "Credentials of the Filesystem source without an fs should be rejected.",
+			creds:  namespacedv1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceFilesystem},
+			want:   errors.Errorf(errMissingSelector, xpv1.CredentialsSourceFilesystem, "fs"),
+		},
+		"Secret": {
+			reason: "Credentials of the Secret source with a secretRef should be accepted.",
+			creds:  namespacedv1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret, CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: secretRef}},
+		},
+		"None": {
+			reason: "Credentials of the None source need no selector.",
+			creds:  namespacedv1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceNone},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := checkCredentialsConfigured(tc.creds)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncheckCredentialsConfigured(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExtractCredentialsNotConfigured(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		creds  namespacedv1beta1.ProviderCredentials
+		want   error
+	}{
+		"EmptySource": {
+			reason: "Credentials without a source should fail before the extractor is called.",
+			want:   errors.Wrap(errors.New(errNoCredentialsSource), errCredentialsNotConfigured),
+		},
+		"MissingSecretRef": {
+			reason: "Credentials of the Secret source without a secretRef should fail before the extractor is called.",
+			creds:  namespacedv1beta1.ProviderCredentials{Source: xpv1.CredentialsSourceSecret},
+			want:   errors.Wrap(errors.Errorf(errMissingSelector, xpv1.CredentialsSourceSecret, "secretRef"), errCredentialsNotConfigured),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			extractions := 0
+			pcSpec := &namespacedv1beta1.ProviderConfigSpec{Credentials: tc.creds}
+			_, err := extractCredentials(context.Background(), testClient(t), countingExtractor(&extractions), pcSpec)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nextractCredentials(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if extractions != 0 {
+				t.Errorf("\n%s\nextractCredentials(...): want no extraction, got %d", tc.reason, extractions)
+			}
+		})
+	}
+}
+
+func TestCheckFileUnchanged(t *testing.T) {
+	errBoom := errors.New("boom")
+	selectors := xpv1.CommonCredentialSelectors{Fs: &xpv1.FsSelector{Path: "/etc/dns-v2/credentials.json"}}
+
+	cases := map[string]struct {
+		reason string
+		again  []byte
+		err    error
+		want   error
+	}{
+		"Unchanged": {
+			reason: "A file read again with the same content should be accepted.",
+			again:  []byte(`{"server":"ns1.example.com"}`),
+		},
+		"Rotating": {
+			reason: "A file read again with other content should return a CredentialsRotatingError.",
+			again:  []byte(`{"server":"ns2.exa`),
+			want:   &CredentialsRotatingError{},
+		},
+		"ReadFailed": {
+			reason: "A file that cannot be read again should return the error of the read.",
+			err:    errBoom,
+			want:   errBoom,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			extract := func(_ context.Context, source xpv1.CredentialsSource, _ client.Client, s xpv1.CommonCredentialSelectors) ([]byte, error) {
+				if source != xpv1.CredentialsSourceFilesystem || s.Fs.Path != selectors.Fs.Path {
+					t.Errorf("extract(...): want the file %s read again, got source %s and selectors %v", selectors.Fs.Path, source, s)
+				}
+				return tc.again, tc.err
+			}
+			err := checkFileUnchanged(context.Background(), nil, extract, selectors, []byte(`{"server":"ns1.example.com"}`))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncheckFileUnchanged(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTerraformSetupFilesystem(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(path, []byte(`{"server":"ns1.example.com"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fsCreds := func(spec *namespacedv1beta1.ProviderConfigSpec) {
+		spec.Credentials = namespacedv1beta1.ProviderCredentials{
+			Source:                    xpv1.CredentialsSourceFilesystem,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{Fs: &xpv1.FsSelector{Path: path}},
+		}
+	}
+
+	// rotating reads the file as rewritten in place, returning its old
+	// content on the first read and the new one on any later read.
+	rotating := func() CredentialExtractor {
+		reads := 0
+		return func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+			reads++
+			if reads == 1 {
+				return []byte(`{"server":"ns1.example.com"}`), nil
+			}
+			return []byte(`{"server":"ns2.example.com"}`), nil
+		}
+	}
+
+	type want struct {
+		update   []any
+		rotating bool
+	}
+
+	cases := map[string]struct {
+		reason  string
+		extract CredentialExtractor
+		want    want
+	}{
+		"Read": {
+			reason:  "The credentials should be read from the file of the Filesystem source.",
+			extract: resource.CommonCredentialExtractor,
+			want:    want{update: []any{map[string]any{keyServer: "ns1.example.com"}}},
+		},
+		"Rotating": {
+			reason:  "A file changing while it is read should return a transient CredentialsRotatingError.",
+			extract: rotating(),
+			want:    want{rotating: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			setup := TerraformSetupBuilder("1.5.7", "hashicorp/dns", "3.4.0", WithCredentialExtractor(tc.extract))
+			ps, err := setup(context.Background(), testClient(t, testProviderConfigWith(fsCreds)), testRecordSet())
+			if got := IsCredentialsRotating(err); got != tc.want.rotating {
+				t.Fatalf("\n%s\nsetup(...): want a CredentialsRotatingError %t, got %v", tc.reason, tc.want.rotating, err)
+			}
+			if tc.want.rotating {
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nsetup(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.update, ps.Configuration[update]); diff != "" {
+				t.Errorf("\n%s\nsetup(...): -want update, +got update:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+const (
+	errParseServers       = "cannot parse servers as a JSON array of strings"
+	errInvalidServer      = "server %q is not a valid IPv6 address or host name"
+	errNoResponsiveServer = "none of the configured servers responded: %s"
+
+	// keyServers lists update targets in priority order, either comma
+	// separated or as a JSON array.
+	keyServers = "servers"
+
+	// serverProbeTimeout bounds the probe of a single server.
+	serverProbeTimeout = 2 * time.Second
+
+	// serverSelectionTTL is how long a server selected among several is used
+	// before the servers are probed again.
+	serverSelectionTTL = 30 * time.Second
+)
+
+// normalizeServer returns the given server in the form expected by the
+// provider, which joins it with the port using net.JoinHostPort: IPv6
+// addresses are returned without brackets, as JoinHostPort adds them.
+func normalizeServer(server string) (string, error) {
+	host := strings.TrimSpace(server)
+	bracketed := strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]")
+	if bracketed {
+		host = host[1 : len(host)-1]
+	}
+	if !bracketed && !strings.Contains(host, ":") {
+		return host, nil
+	}
+	if ip := net.ParseIP(host); ip == nil || (bracketed && ip.To4() != nil) {
+		return "", errors.Errorf(errInvalidServer, server)
+	}
+	return host, nil
+}
+
+// serverList returns the servers of the credentials in priority order: the
+// server key first, followed by the servers key.
+func serverList(creds map[string]string) ([]string, error) {
+	var servers []string
+	if server := creds[keyServer]; server != "" {
+		normalized, err := normalizeServer(server)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, normalized)
+	}
+
+	value := strings.TrimSpace(creds[keyServers])
+	if value == "" {
+		return servers, nil
+	}
+	var listed []string
+	if strings.HasPrefix(value, "[") {
+		if err := json.Unmarshal([]byte(value), &listed); err != nil {
+			return nil, errors.Wrap(err, errParseServers)
+		}
+	} else {
+		listed = strings.Split(value, ",")
+	}
+	for _, s := range listed {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		normalized, err := normalizeServer(s)
+		if err != nil {
+			return nil, err
+		}
+		servers = append(servers, normalized)
+	}
+	return servers, nil
+}
+
+// A serverSelector selects the server of the credentials among their servers.
+// The Terraform provider only accepts a single server, so failover happens at
+// setup time. Selections are cached for the zone, servers, port and transport
+// they were probed with, so that a server that is down only delays a setup
+// once per TTL rather than every reconcile of every resource.
+type serverSelector struct {
+	ttl   time.Duration
+	probe func(ctx context.Context, server string, port int, transport, zone string) error
+
+	mu       sync.Mutex
+	selected map[serverSelectionKey]serverSelection
+}
+
+type serverSelectionKey struct {
+	servers   string
+	port      int
+	transport string
+	zone      string
+}
+
+type serverSelection struct {
+	server string
+	at     time.Time
+}
+
+func newServerSelector(ttl time.Duration) *serverSelector {
+	return &serverSelector{ttl: ttl, probe: probeServer, selected: map[serverSelectionKey]serverSelection{}}
+}
+
+// Select sets the server of the credentials to the first of their servers
+// that answers a query for the given zone, using the port and transport of
+// the credentials, which must therefore be final. Servers are only probed
+// when more than one is configured.
+func (s *serverSelector) Select(ctx context.Context, creds map[string]string, zone string) error {
+	servers, err := serverList(creds)
+	if err != nil {
+		return err
+	}
+	switch len(servers) {
+	case 0:
+		return nil
+	case 1:
+		creds[keyServer] = servers[0]
+		return nil
+	}
+
+	port, err := resolvePort(creds)
+	if err != nil {
+		return err
+	}
+	if port == 0 {
+		port = defaultPort
+	}
+	transport := creds[keyTransport]
+	if transport == "" {
+		transport = defaultTransport
+	}
+
+	key := serverSelectionKey{servers: strings.Join(servers, ","), port: port, transport: transport, zone: zone}
+	now := time.Now()
+	if server, ok := s.cached(key, now); ok {
+		creds[keyServer] = server
+		return nil
+	}
+
+	var failures []string
+	for _, server := range servers {
+		if err := s.probe(ctx, server, port, transport, zone); err != nil {
+			failures = append(failures, server+": "+err.Error())
+			continue
+		}
+		s.store(key, server, now)
+		creds[keyServer] = server
+		return nil
+	}
+	return errors.Errorf(errNoResponsiveServer, strings.Join(failures, "; "))
+}
+
+// cached returns the server selected for the given key within the TTL.
+func (s *serverSelector) cached(key serverSelectionKey, now time.Time) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sel, ok := s.selected[key]
+	if !ok || now.Sub(sel.at) >= s.ttl {
+		return "", false
+	}
+	return sel.server, true
+}
+
+// store caches the server selected for the given key, dropping expired
+// selections so that the cache does not grow with zones no longer used.
+func (s *serverSelector) store(key serverSelectionKey, server string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, sel := range s.selected {
+		if now.Sub(sel.at) >= s.ttl {
+			delete(s.selected, k)
+		}
+	}
+	s.selected[key] = serverSelection{server: server, at: now}
+}
+
+// probeServer sends a SOA query for the given zone to the server. Any answer,
+// including an error response code, shows that the server is responsive.
+func probeServer(ctx context.Context, server string, port int, transport, zone string) error {
+	if zone == "" {
+		zone = "."
+	}
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(zone), dns.TypeSOA)
+
+	ctx, cancel := context.WithTimeout(ctx, serverProbeTimeout)
+	defer cancel()
+
+	c := &dns.Client{Net: transport, Timeout: serverProbeTimeout}
+	_, _, err := c.ExchangeContext(ctx, msg, net.JoinHostPort(server, strconv.Itoa(port)))
+	return err
+}
@@ -0,0 +1,119 @@
+package clients
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+// fakeProbe answers probes of the servers it lists as up, and records every
+// probe it receives.
+type fakeProbe struct {
+	up     map[string]bool
+	probes []string
+}
+
+func (f *fakeProbe) probe(_ context.Context, server string, _ int, transport, _ string) error {
+	f.probes = append(f.probes, server+"/"+transport)
+	if !f.up[server] {
+		return errors.New("timeout")
+	}
+	return nil
+}
+
+func TestServerSelectorSelect(t *testing.T) {
+	type want struct {
+		server string
+		probes []string
+		err    bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		up     map[string]bool
+		creds  []map[string]string
+		want   want
+	}{
+		"SingleServer": {
+			reason: "A single server should be used without probing it.",
+			creds:  []map[string]string{{keyServer: "ns1.example.com"}},
+			want:   want{server: "ns1.example.com"},
+		},
+		"Failover": {
+			reason: "The first responsive server should be selected.",
+			up:     map[string]bool{"ns2.example.com": true},
+			creds:  []map[string]string{{keyServer: "ns1.example.com", keyServers: "ns2.example.com"}},
+			want:   want{server: "ns2.example.com", probes: []string{"ns1.example.com/udp", "ns2.example.com/udp"}},
+		},
+		"Cached": {
+			reason: "A selection should be reused within the TTL instead of probing again.",
+			up:     map[string]bool{"ns2.example.com": true},
+			creds: []map[string]string{
+				{keyServer: "ns1.example.com", keyServers: "ns2.example.com"},
+				{keyServer: "ns1.example.com", keyServers: "ns2.example.com"},
+			},
+			want: want{server: "ns2.example.com", probes: []string{"ns1.example.com/udp", "ns2.example.com/udp"}},
+		},
+		"TransportNotCached": {
+			reason: "A selection should not be reused for a different transport.",
+			up:     map[string]bool{"ns1.example.com": true},
+			creds: []map[string]string{
+				{keyServer: "ns1.example.com", keyServers: "ns2.example.com"},
+				{keyServer: "ns1.example.com", keyServers: "ns2.example.com", keyTransport: "tcp"},
+			},
+			want: want{server: "ns1.example.com", probes: []string{"ns1.example.com/udp", "ns1.example.com/tcp"}},
+		},
+		"NoneResponsive": {
+			reason: "An error should be returned when no server responds.",
+			creds:  []map[string]string{{keyServer: "ns1.example.com", keyServers: "ns2.example.com"}},
+			want:   want{probes: []string{"ns1.example.com/udp", "ns2.example.com/udp"}, err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &fakeProbe{up: tc.up}
+			s := newServerSelector(time.Minute)
+			s.probe = f.probe
+
+			var err error
+			var creds map[string]string
+			for _, creds = range tc.creds {
+				if err = s.Select(context.Background(), creds, "example.com."); err != nil {
+					break
+				}
+			}
+			if gotErr := err != nil; gotErr != tc.want.err {
+				t.Errorf("\n%s\nSelect(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+			if !tc.want.err {
+				if diff := cmp.Diff(tc.want.server, creds[keyServer]); diff != "" {
+					t.Errorf("\n%s\nSelect(...): -want server, +got server:\n%s", tc.reason, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.probes, f.probes); diff != "" {
+				t.Errorf("\n%s\nSelect(...): -want probes, +got probes:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServerSelectorExpiry(t *testing.T) {
+	f := &fakeProbe{up: map[string]bool{"ns1.example.com": true}}
+	s := newServerSelector(time.Minute)
+	s.probe = f.probe
+
+	key := serverSelectionKey{servers: "ns1.example.com,ns2.example.com", port: defaultPort, transport: defaultTransport, zone: "example.com."}
+	s.store(key, "ns2.example.com", time.Now().Add(-2*time.Minute))
+
+	creds := map[string]string{keyServer: "ns1.example.com", keyServers: "ns2.example.com"}
+	if err := s.Select(context.Background(), creds, "example.com."); err != nil {
+		t.Fatalf("Select(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("ns1.example.com", creds[keyServer]); diff != "" {
+		t.Errorf("Select(...): an expired selection should be probed again: -want server, +got server:\n%s", diff)
+	}
+}
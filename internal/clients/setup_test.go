@@ -0,0 +1,336 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/dana-team/provider-dns-v2/apis/cluster"
+	clusterrecordsetv1alpha1 "github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1"
+	clusterv1beta1 "github.com/dana-team/provider-dns-v2/apis/cluster/v1beta1"
+	"github.com/dana-team/provider-dns-v2/apis/namespaced"
+	recordsetv1alpha1 "github.com/dana-team/provider-dns-v2/apis/namespaced/recordset/v1alpha1"
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+const (
+	testNamespace      = "default"
+	testConfigName     = "default"
+	testSecretName     = "dns-creds"
+	testSecretKey      = "credentials"
+	testProviderConfig = "pc-uid"
+)
+
+func to[T any](v T) *T { return &v }
+
+// testScheme returns a scheme of the Kubernetes and provider types.
+func testScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, cluster.AddToScheme, namespaced.AddToScheme} {
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+// testClient returns a fake client holding the given objects.
+func testClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	return fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(objs...).Build()
+}
+
+// testRecordSet returns a namespaced A record set using the test
+// ProviderConfig.
+func testRecordSet() *recordsetv1alpha1.ARecordSet {
+	rs := &recordsetv1alpha1.ARecordSet{}
+	rs.SetName("www")
+	rs.SetNamespace(testNamespace)
+	rs.SetUID("rs-uid")
+	rs.SetProviderConfigReference(&xpv1.ProviderConfigReference{Kind: namespacedv1beta1.ProviderConfigKind, Name: testConfigName})
+	rs.Spec.ForProvider.Zone = to("example.com.")
+	rs.Spec.ForProvider.Name = to("www")
+	rs.Spec.ForProvider.Addresses = []*string{to("192.0.2.1")}
+	rs.Spec.ForProvider.TTL = to[int64](300)
+	return rs
+}
+
+// testProviderConfigWith returns the test ProviderConfig reading its
+// credentials from the test Secret.
+func testProviderConfigWith(mod func(*namespacedv1beta1.ProviderConfigSpec)) *namespacedv1beta1.ProviderConfig {
+	pc := &namespacedv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: testConfigName, Namespace: testNamespace, UID: testProviderConfig, Generation: 1},
+		Spec: namespacedv1beta1.ProviderConfigSpec{Credentials: namespacedv1beta1.ProviderCredentials{
+			Source:                    xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: testSecretName}, Key: testSecretKey}},
+		}},
+	}
+	if mod != nil {
+		mod(&pc.Spec)
+	}
+	return pc
+}
+
+// testSecret returns the test Secret holding the given credentials.
+func testSecret(creds string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testSecretName, Namespace: testNamespace},
+		Data:       map[string][]byte{testSecretKey: []byte(creds)},
+	}
+}
+
+// countingExtractor extracts credentials with the common extractor and counts
+// the extractions.
+func countingExtractor(n *int) CredentialExtractor {
+	return func(ctx context.Context, source xpv1.CredentialsSource, c client.Client, s xpv1.CommonCredentialSelectors) ([]byte, error) {
+		*n++
+		return resource.CommonCredentialExtractor(ctx, source, c, s)
+	}
+}
+
+func TestTerraformSetup(t *testing.T) {
+	noRef := testRecordSet()
+	noRef.SetProviderConfigReference(nil)
+
+	type want struct {
+		update []any
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		objs   []client.Object
+		want   want
+	}{
+		"SecretTSIG": {
+			reason: "TSIG credentials should be built into an update block holding the server, the optional configuration and the normalized key.",
+			mg:     testRecordSet(),
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"rfc":"2845","server":"ns1.example.com","port":"5353","retries":"2","timeout":"5s","transport":"tcp","key_name":"tsig","key_algorithm":"hmac-sha256.","key_secret":"c2VjcmV0"}`)},
+			want: want{update: []any{map[string]any{
+				keyServer:               "ns1.example.com",
+				keyPort:                 5353,
+				keyRetries:              2,
+				keyTimeout:              "5s",
+				keyTransport:            "tcp",
+				transcationKeyName:      "tsig.",
+				transactionKeyAlgorithm: "hmac-sha256",
+				transactionKeySecret:    "c2VjcmV0",
+			}}},
+		},
+		"GSSTSIGPassword": {
+			reason: "GSS-TSIG credentials with a password should be built into a gssapi block with the bare username and the upper case realm.",
+			mg:     testRecordSet(),
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"rfc":"3645","server":"ns1.example.com","realm":"example.com","username":"dns@example.com","password":"p4ssw0rd"}`)},
+			want: want{update: []any{map[string]any{
+				keyServer: "ns1.example.com",
+				gssapi: []any{map[string]any{
+					keyRealm:    "EXAMPLE.COM",
+					keyUsername: "dns",
+					keyPassword: "p4ssw0rd",
+				}},
+			}}},
+		},
+		"GSSTSIGKeytab": {
+			reason: "GSS-TSIG credentials with the path of a keytab should be built into a gssapi block holding the path.",
+			mg:     testRecordSet(),
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"rfc":"3645","server":"ns1.example.com","realm":"EXAMPLE.COM","username":"dns","keytab":"/etc/krb5/dns.keytab"}`)},
+			want: want{update: []any{map[string]any{
+				keyServer: "ns1.example.com",
+				gssapi: []any{map[string]any{
+					keyRealm:    "EXAMPLE.COM",
+					keyUsername: "dns",
+					keyTab:      "/etc/krb5/dns.keytab",
+				}},
+			}}},
+		},
+		"Unauthenticated": {
+			reason: "Credentials without an rfc should be built into an update block holding only the server.",
+			mg:     testRecordSet(),
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"server":"ns1.example.com"}`)},
+			want:   want{update: []any{map[string]any{keyServer: "ns1.example.com"}}},
+		},
+		"ConnectionSettings": {
+			reason: "The connection settings of the ProviderConfig should be used for the keys the credentials do not set.",
+			mg:     testRecordSet(),
+			objs: []client.Object{
+				testProviderConfigWith(func(spec *namespacedv1beta1.ProviderConfigSpec) {
+					spec.Connection = &namespacedv1beta1.ConnectionSettings{Server: "ns2.example.com", Port: to(5353), Transport: "tcp"}
+				}),
+				testSecret(`{"rfc":"2845","transport":"udp","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"c2VjcmV0"}`),
+			},
+			want: want{update: []any{map[string]any{
+				keyServer:               "ns2.example.com",
+				keyPort:                 5353,
+				keyTransport:            "udp",
+				transcationKeyName:      "tsig.",
+				transactionKeyAlgorithm: "hmac-sha256",
+				transactionKeySecret:    "c2VjcmV0",
+			}}},
+		},
+		"ClampedRetries": {
+			reason: "Retries above the maxRetries of the ProviderConfig should be lowered to it in the update block.",
+			mg:     testRecordSet(),
+			objs: []client.Object{
+				testProviderConfigWith(func(spec *namespacedv1beta1.ProviderConfigSpec) { spec.MaxRetries = to(2) }),
+				testSecret(`{"server":"ns1.example.com","retries":"1000"}`),
+			},
+			want: want{update: []any{map[string]any{keyServer: "ns1.example.com", keyRetries: 2}}},
+		},
+		"NoProviderConfigRef": {
+			reason: "A managed resource without a providerConfigRef should fail.",
+			mg:     noRef,
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"server":"ns1.example.com"}`)},
+			want:   want{err: errors.Wrap(errors.New(errNoProviderConfig), "cannot resolve provider config")},
+		},
+		"ProviderConfigNotFound": {
+			reason: "A managed resource referencing a missing ProviderConfig should fail.",
+			mg:     testRecordSet(),
+			objs:   []client.Object{testSecret(`{"server":"ns1.example.com"}`)},
+			want:   want{err: errors.Wrap(errors.Wrap(kerrors.NewNotFound(schema.GroupResource{Group: namespacedv1beta1.Group, Resource: "providerconfigs"}, testConfigName), errGetProviderConfig), "cannot resolve provider config")},
+		},
+		"InvalidJSON": {
+			reason: "Credentials that are not JSON should fail without quoting them.",
+			mg:     testRecordSet(),
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"server":`)},
+			want:   want{err: unmarshalCredentialsError(json.Unmarshal([]byte(`{"server":`), &map[string]string{}))},
+		},
+		"MissingServer": {
+			reason: "Credentials without a server should fail.",
+			mg:     testRecordSet(),
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"rfc":"2845","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"c2VjcmV0"}`)},
+			want:   want{err: errors.Wrap(errors.New(errMissingServer), errBuildAuthConfig)},
+		},
+		"InvalidKeyAlgorithm": {
+			reason: "TSIG credentials with an unsupported key_algorithm should fail.",
+			mg:     testRecordSet(),
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"rfc":"2845","server":"ns1.example.com","key_name":"tsig.","key_algorithm":"hmac-sha3","key_secret":"c2VjcmV0"}`)},
+			want:   want{err: errors.Wrap(errors.Errorf(errInvalidKeyAlgorithm, "hmac-sha3", strings.Join(keyAlgorithms, ", ")), errBuildAuthConfig)},
+		},
+		"GSSTSIGMissingKeys": {
+			reason: "GSS-TSIG credentials without a realm and username should fail.",
+			mg:     testRecordSet(),
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"rfc":"3645","server":"ns1.example.com","password":"p4ssw0rd"}`)},
+			want:   want{err: errors.Wrap(errors.Errorf(errMissingGSSTSIGKeys, "realm, username"), errBuildAuthConfig)},
+		},
+		"GSSTSIGPasswordAndKeytab": {
+			reason: "GSS-TSIG credentials with both a password and a keytab should fail.",
+			mg:     testRecordSet(),
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"rfc":"3645","server":"ns1.example.com","realm":"EXAMPLE.COM","username":"dns","password":"p4ssw0rd","keytab":"/etc/krb5/dns.keytab"}`)},
+			want:   want{err: errors.Wrap(errors.Errorf(errGSSTSIGAuthMethod, keyPassword, keyTab), errBuildAuthConfig)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			setup := TerraformSetupBuilder("1.5.7", "hashicorp/dns", "3.4.0", WithCredentialExtractor(resource.CommonCredentialExtractor))
+			ps, err := setup(context.Background(), testClient(t, tc.objs...), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nsetup(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.update, ps.Configuration[update]); diff != "" {
+				t.Errorf("\n%s\nsetup(...): -want update, +got update:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTerraformSetupEnvironment(t *testing.T) {
+	const env = "DNS_V2_TEST_CREDENTIALS"
+	envCreds := namespacedv1beta1.ProviderCredentials{
+		Source:                    xpv1.CredentialsSourceEnvironment,
+		CommonCredentialSelectors: xpv1.CommonCredentialSelectors{Env: &xpv1.EnvSelector{Name: env}},
+	}
+
+	legacyRecordSet := &clusterrecordsetv1alpha1.ARecordSet{}
+	legacyRecordSet.SetName("www")
+	legacyRecordSet.SetUID("legacy-uid")
+	legacyRecordSet.SetProviderConfigReference(&xpv1.Reference{Name: testConfigName})
+	legacyRecordSet.Spec.ForProvider.Zone = to("example.com.")
+	legacyRecordSet.Spec.ForProvider.Name = to("www")
+	legacyRecordSet.Spec.ForProvider.Addresses = []*string{to("192.0.2.1")}
+	legacyProviderConfig := &clusterv1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: testConfigName},
+		Spec:       clusterv1beta1.ProviderConfigSpec{Credentials: clusterv1beta1.ProviderCredentials(envCreds)},
+	}
+
+	tsig := `{"rfc":"2845","server":"ns1.example.com","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"c2VjcmV0"}`
+	tsigUpdate := []any{map[string]any{
+		keyServer:               "ns1.example.com",
+		transcationKeyName:      "tsig.",
+		transactionKeyAlgorithm: "hmac-sha256",
+		transactionKeySecret:    "c2VjcmV0",
+	}}
+
+	type want struct {
+		update []any
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		value  *string
+		mg     resource.Managed
+		objs   []client.Object
+		want   want
+	}{
+		"Modern": {
+			reason: "A namespaced resource should read its credentials from the variable named by credentials.env.name.",
+			value:  to(tsig),
+			mg:     testRecordSet(),
+			objs:   []client.Object{testProviderConfigWith(func(spec *namespacedv1beta1.ProviderConfigSpec) { spec.Credentials = envCreds })},
+			want:   want{update: tsigUpdate},
+		},
+		"Legacy": {
+			reason: "A cluster-scoped resource should read its credentials from the variable named by credentials.env.name.",
+			value:  to(tsig),
+			mg:     legacyRecordSet,
+			objs:   []client.Object{legacyProviderConfig},
+			want:   want{update: tsigUpdate},
+		},
+		"NotSet": {
+			reason: "An unset variable should read as empty credentials, which lack a server.",
+			mg:     testRecordSet(),
+			objs:   []client.Object{testProviderConfigWith(func(spec *namespacedv1beta1.ProviderConfigSpec) { spec.Credentials = envCreds })},
+			want:   want{err: errors.Wrap(errors.New(errMissingServer), errBuildAuthConfig)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if tc.value != nil {
+				t.Setenv(env, *tc.value)
+			}
+			setup := TerraformSetupBuilder("1.5.7", "hashicorp/dns", "3.4.0")
+			ps, err := setup(context.Background(), testClient(t, tc.objs...), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nsetup(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.update, ps.Configuration[update]); diff != "" {
+				t.Errorf("\n%s\nsetup(...): -want update, +got update:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+const (
+	errMarshalSummary    = "cannot marshal provider config summary as JSON"
+	errCredentialsSyntax = "%s: invalid JSON at offset %d"
+	errCredentialsType   = "%s: key %q holds a %s instead of a string"
+
+	// redactedValue replaces secret credential values wherever credentials are exposed.
+	redactedValue = "REDACTED"
+)
+
+// secretCredentialKeys are the credential keys whose values must never be exposed.
+var secretCredentialKeys = []string{keyPassword, keyTab, transactionKeySecret}
+
+// ProviderConfigSummary is a machine-readable summary of the effective settings
+// of a resolved ProviderConfig, meant for tooling such as kubectl plugins.
+type ProviderConfigSummary struct {
+	// CredentialsSource is the source the credentials were extracted from.
+	CredentialsSource string `json:"credentialsSource"`
+
+	Server    string `json:"server,omitempty"`
+	Port      string `json:"port,omitempty"`
+	RFC       string `json:"rfc,omitempty"`
+	Transport string `json:"transport,omitempty"`
+	Timeout   string `json:"timeout,omitempty"`
+	Retries   string `json:"retries,omitempty"`
+
+	// Credentials are the extracted credentials with secret values redacted.
+	Credentials map[string]string `json:"credentials"`
+}
+
+// SummarizeProviderConfig returns a redacted JSON summary of the effective
+// settings of the given resolved ProviderConfig spec and its credentials.
+func SummarizeProviderConfig(pcSpec *namespacedv1beta1.ProviderConfigSpec, creds map[string]string) ([]byte, error) {
+	optionalConfig, err := buildOptionalConfig(creds, pcSpec != nil && pcSpec.ExplicitDefaults)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := ProviderConfigSummary{
+		Server:      creds[keyServer],
+		RFC:         creds[keyRFC],
+		Port:        stringValue(optionalConfig[keyPort]),
+		Transport:   stringValue(optionalConfig[keyTransport]),
+		Timeout:     stringValue(optionalConfig[keyTimeout]),
+		Retries:     stringValue(optionalConfig[keyRetries]),
+		Credentials: redactCredentials(creds),
+	}
+	if pcSpec != nil {
+		summary.CredentialsSource = string(pcSpec.Credentials.Source)
+	}
+
+	data, err := json.Marshal(summary)
+	return data, errors.Wrap(err, errMarshalSummary)
+}
+
+// redactCredentials returns a copy of the given credentials with the values of
+// all secret keys replaced.
+func redactCredentials(creds map[string]string) map[string]string {
+	redacted := make(map[string]string, len(creds))
+	for k, v := range creds {
+		redacted[k] = v
+	}
+	for _, k := range secretCredentialKeys {
+		if _, ok := redacted[k]; ok {
+			redacted[k] = redactedValue
+		}
+	}
+	return redacted
+}
+
+// redactError returns the given error with the values of all secret keys of
+// the credentials replaced in its message. The error is returned unchanged
+// when its message holds no secret value.
+func redactError(err error, creds map[string]string) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	redacted := msg
+	for _, k := range secretCredentialKeys {
+		if v := creds[k]; v != "" {
+			redacted = strings.ReplaceAll(redacted, v, redactedValue)
+		}
+	}
+	if redacted == msg {
+		return err
+	}
+	return errors.New(redacted)
+}
+
+// unmarshalCredentialsError returns an error for credentials that cannot be
+// unmarshalled without quoting any part of them.
+func unmarshalCredentialsError(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return errors.Errorf(errCredentialsSyntax, errUnmarshalCredentials, syntaxErr.Offset)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return errors.Errorf(errCredentialsType, errUnmarshalCredentials, typeErr.Field, typeErr.Value)
+	}
+	return errors.New(errUnmarshalCredentials)
+}
+
+// stringValue formats an optional configuration value, returning an empty
+// string when it is not set.
+func stringValue(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,154 @@
+package clients
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+// secret is a credential value that must never appear in an error.
+const secret = "s3cr3t-v4lue"
+
+func TestRedactCredentials(t *testing.T) {
+	creds := map[string]string{
+		keyServer:            "ns1.example.com",
+		keyPassword:          secret,
+		keyTab:               secret,
+		transactionKeySecret: secret,
+	}
+	want := map[string]string{
+		keyServer:            "ns1.example.com",
+		keyPassword:          redactedValue,
+		keyTab:               redactedValue,
+		transactionKeySecret: redactedValue,
+	}
+
+	got := redactCredentials(creds)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("redactCredentials(...): -want, +got:\n%s", diff)
+	}
+	if creds[keyPassword] != secret {
+		t.Errorf("redactCredentials(...): must not modify the given credentials")
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		creds  map[string]string
+		want   error
+	}{
+		"Nil": {
+			reason: "A nil error should be returned unchanged.",
+			creds:  map[string]string{keyPassword: secret},
+		},
+		"NoSecret": {
+			reason: "An error holding no secret value should be returned unchanged.",
+			err:    errBoom,
+			creds:  map[string]string{keyPassword: secret},
+			want:   errBoom,
+		},
+		"Password": {
+			reason: "A password in the message should be redacted.",
+			err:    errors.Errorf("timeout must be a duration, got %q", secret),
+			creds:  map[string]string{keyPassword: secret},
+			want:   errors.Errorf("timeout must be a duration, got %q", redactedValue),
+		},
+		"KeySecret": {
+			reason: "A key_secret in a wrapped message should be redacted.",
+			err:    errors.Wrap(errors.Errorf("port %s is invalid", secret), "cannot build"),
+			creds:  map[string]string{transactionKeySecret: secret},
+			want:   errors.New("cannot build: port REDACTED is invalid"),
+		},
+		"Keytab": {
+			reason: "A keytab path in the message should be redacted.",
+			err:    errors.Errorf("open /etc/%s: no such file or directory", secret),
+			creds:  map[string]string{keyTab: "/etc/" + secret},
+			want:   errors.New("open REDACTED: no such file or directory"),
+		},
+		"EmptySecret": {
+			reason: "An empty secret value should not redact anything.",
+			err:    errBoom,
+			creds:  map[string]string{keyPassword: ""},
+			want:   errBoom,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := redactError(tc.err, tc.creds)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nredactError(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestErrorsDoNotLeakSecrets(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		creds  string
+	}{
+		"SecretAsPort": {
+			reason: "A key_secret pasted into the port should not be quoted by the port validation.",
+			creds:  `{"rfc":"2845","server":"ns1.example.com","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"` + secret + `","port":"` + secret + `"}`,
+		},
+		"PasswordAsTimeout": {
+			reason: "A password pasted into the timeout should not be quoted by the timeout validation.",
+			creds:  `{"rfc":"3645","server":"ns1.example.com","username":"dns","realm":"EXAMPLE.COM","password":"` + secret + `","timeout":"` + secret + `"}`,
+		},
+		"PasswordAsRetries": {
+			reason: "A password pasted into retries should not be quoted by the retries validation.",
+			creds:  `{"rfc":"3645","server":"ns1.example.com","username":"dns","realm":"EXAMPLE.COM","password":"` + secret + `","retries":"` + secret + `"}`,
+		},
+		"InvalidJSON": {
+			reason: "Credentials that are not valid JSON should not be quoted.",
+			creds:  `{"rfc":"2845","key_secret":"` + secret + `"`,
+		},
+		"NotAString": {
+			reason: "A secret of the wrong type should not be quoted.",
+			creds:  `{"rfc":"2845","key_secret":["` + secret + `"]}`,
+		},
+		"InvalidBase64Keytab": {
+			reason: "A keytab that is not a valid base64 keytab should not be quoted.",
+			creds:  `{"rfc":"3645","server":"ns1.example.com","username":"dns","realm":"EXAMPLE.COM","keytab":"` + secret + `","keytab_encoding":"base64"}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := PreviewProviderConfig([]byte(tc.creds), nil)
+			if err == nil {
+				t.Fatalf("\n%s\nPreviewProviderConfig(...): want error, got nil", tc.reason)
+			}
+			if strings.Contains(err.Error(), secret) {
+				t.Errorf("\n%s\nPreviewProviderConfig(...): error leaks the secret: %v", tc.reason, err)
+			}
+		})
+	}
+}
+
+func TestBuildAuthConfigDoesNotLeakSecrets(t *testing.T) {
+	creds := map[string]string{
+		keyRFC:                  keyBasedTransactionRFC,
+		keyServer:               "ns1.example.com",
+		transcationKeyName:      "tsig.",
+		transactionKeyAlgorithm: "hmac-sha256",
+		transactionKeySecret:    secret,
+		keyPort:                 secret,
+	}
+
+	_, err := BuildAuthConfig(creds)
+	if err == nil {
+		t.Fatal("BuildAuthConfig(...): want error, got nil")
+	}
+	if strings.Contains(err.Error(), secret) {
+		t.Errorf("BuildAuthConfig(...): error leaks the secret: %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package clients
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracer traces the setup of managed resources. It uses the global tracer
+// provider, which does not record anything unless one is configured.
+var tracer = otel.Tracer("github.com/dana-team/provider-dns-v2/internal/clients")
+
+// Span attributes. Their values are bounded and never hold secrets.
+const (
+	attrOutcome  = attribute.Key("dns_v2.outcome")
+	attrScope    = attribute.Key("dns_v2.scope")
+	attrAuthMode = attribute.Key("dns_v2.auth_mode")
+
+	scopeLegacy = "legacy"
+	scopeModern = "modern"
+)
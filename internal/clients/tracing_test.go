@@ -0,0 +1,89 @@
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// recordedSpan is the part of a recorded span the tests compare.
+type recordedSpan struct {
+	Name       string
+	Parent     string
+	Attributes map[attribute.Key]string
+	Status     codes.Code
+}
+
+// recordedSpans returns the spans recorded by the given recorder, with the
+// name of their parent among the recorded spans.
+func recordedSpans(sr *tracetest.SpanRecorder) []recordedSpan {
+	names := map[string]string{}
+	for _, s := range sr.Ended() {
+		names[s.SpanContext().SpanID().String()] = s.Name()
+	}
+	spans := make([]recordedSpan, 0, len(sr.Ended()))
+	for _, s := range sr.Ended() {
+		attrs := map[attribute.Key]string{}
+		for _, a := range s.Attributes() {
+			attrs[a.Key] = a.Value.Emit()
+		}
+		spans = append(spans, recordedSpan{
+			Name:       s.Name(),
+			Parent:     names[s.Parent().SpanID().String()],
+			Attributes: attrs,
+			Status:     s.Status().Code,
+		})
+	}
+	return spans
+}
+
+func TestTerraformSetupSpans(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+
+	cases := map[string]struct {
+		reason string
+		objs   []client.Object
+		want   []recordedSpan
+	}{
+		"Success": {
+			reason: "A successful setup should record its steps as children of the TerraformSetup span, in the order they ended.",
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"rfc":"2845","server":"ns1.example.com","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"c2VjcmV0"}`)},
+			want: []recordedSpan{
+				{Name: "ResolveProviderConfig", Parent: "TerraformSetup", Attributes: map[attribute.Key]string{attrScope: scopeModern}},
+				{Name: "ExtractCredentials", Parent: "TerraformSetup", Attributes: map[attribute.Key]string{}},
+				{Name: "BuildAuthConfig", Parent: "TerraformSetup", Attributes: map[attribute.Key]string{attrAuthMode: "TSIG"}},
+				{Name: "TerraformSetup", Attributes: map[attribute.Key]string{attrOutcome: outcomeSuccess}},
+			},
+		},
+		"MissingServer": {
+			reason: "A failed setup should record the outcome as the error status of the TerraformSetup span.",
+			objs:   []client.Object{testProviderConfigWith(nil), testSecret(`{"rfc":"3645","realm":"EXAMPLE.COM","username":"dns","password":"p4ssw0rd"}`)},
+			want: []recordedSpan{
+				{Name: "ResolveProviderConfig", Parent: "TerraformSetup", Attributes: map[attribute.Key]string{attrScope: scopeModern}},
+				{Name: "ExtractCredentials", Parent: "TerraformSetup", Attributes: map[attribute.Key]string{}},
+				{Name: "BuildAuthConfig", Parent: "TerraformSetup", Attributes: map[attribute.Key]string{attrAuthMode: "GSS-TSIG"}},
+				{Name: "TerraformSetup", Attributes: map[attribute.Key]string{attrOutcome: outcomeMissingServer}, Status: codes.Error},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sr.Reset()
+			setup := TerraformSetupBuilder("1.5.7", "hashicorp/dns", "3.4.0", WithCredentialExtractor(resource.CommonCredentialExtractor))
+			_, _ = setup(context.Background(), testClient(t, tc.objs...), testRecordSet())
+			if diff := cmp.Diff(tc.want, recordedSpans(sr)); diff != "" {
+				t.Errorf("\n%s\nsetup(...): -want spans, +got spans:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+const (
+	// AnnotationTSIGKey selects one of the tsigKeys of the ProviderConfig for
+	// the annotated managed resource.
+	AnnotationTSIGKey = "dns-v2.crossplane.io/tsig-key"
+
+	errUnknownTSIGKey        = "%s annotation selects TSIG key %q, which the ProviderConfig does not define"
+	errTSIGKeyNeedsRFC       = "TSIG key %q requires rfc %s credentials"
+	errTSIGKeyNeedsSecret    = "TSIG key %q requires credentials with a Secret source"
+	errTSIGKeySecretNotFound = "key %q holding the secret of TSIG key %q is not set in the credentials Secret"
+)
+
+// applyTSIGKey replaces the TSIG key of the credentials with the key of the
+// ProviderConfig selected by the annotation of the given managed resource. The
+// secret of the key is read from the credentials Secret. The credentials are
+// left unchanged when no key is selected.
+func applyTSIGKey(ctx context.Context, c client.Client, extract CredentialExtractor, pcSpec *namespacedv1beta1.ProviderConfigSpec, mg resource.Managed, creds map[string]string) error {
+	name, ok := mg.GetAnnotations()[AnnotationTSIGKey]
+	if !ok {
+		return nil
+	}
+
+	var key *namespacedv1beta1.TSIGKey
+	for i := range pcSpec.TSIGKeys {
+		if pcSpec.TSIGKeys[i].Name == name {
+			key = &pcSpec.TSIGKeys[i]
+			break
+		}
+	}
+	if key == nil {
+		return errors.Errorf(errUnknownTSIGKey, AnnotationTSIGKey, name)
+	}
+	if creds[keyRFC] != keyBasedTransactionRFC {
+		return errors.Errorf(errTSIGKeyNeedsRFC, name, keyBasedTransactionRFC)
+	}
+	ref := pcSpec.Credentials.SecretRef
+	if pcSpec.Credentials.Source != xpv1.CredentialsSourceSecret || ref == nil {
+		return errors.Errorf(errTSIGKeyNeedsSecret, name)
+	}
+
+	secretRef := ref.DeepCopy()
+	secretRef.Key = key.SecretKey
+	secret, err := extractCredentialsWith(ctx, c, extract, pcSpec, xpv1.CommonCredentialSelectors{SecretRef: secretRef})
+	if err != nil {
+		return err
+	}
+	if len(secret) == 0 {
+		return errors.Errorf(errTSIGKeySecretNotFound, key.SecretKey, name)
+	}
+
+	creds[transcationKeyName] = key.KeyName
+	creds[transactionKeySecret] = string(secret)
+	if key.KeyAlgorithm != "" {
+		creds[transactionKeyAlgorithm] = key.KeyAlgorithm
+	}
+	return nil
+}
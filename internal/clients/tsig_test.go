@@ -0,0 +1,125 @@
+package clients
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	namespacedv1beta1 "github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+func TestApplyTSIGKey(t *testing.T) {
+	keys := func(spec *namespacedv1beta1.ProviderConfigSpec) {
+		spec.TSIGKeys = []namespacedv1beta1.TSIGKey{
+			{Name: "internal", KeyName: "internal-key.", KeyAlgorithm: "hmac-sha512", SecretKey: "internal"},
+			{Name: "external", KeyName: "external-key.", SecretKey: "external"},
+			{Name: "unset", KeyName: "unset-key.", SecretKey: "unset"},
+		}
+	}
+	tsig := func() map[string]string {
+		return map[string]string{keyRFC: keyBasedTransactionRFC, keyServer: "ns1.example.com", transcationKeyName: "default-key.", transactionKeyAlgorithm: "hmac-sha256", transactionKeySecret: "ZGVmYXVsdA=="}
+	}
+
+	type want struct {
+		creds map[string]string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		key    *string
+		mod    func(*namespacedv1beta1.ProviderConfigSpec)
+		creds  map[string]string
+		want   want
+	}{
+		"NoSelection": {
+			reason: "The single configured key should be kept when no key is selected.",
+			mod:    keys,
+			creds:  tsig(),
+			want:   want{creds: tsig()},
+		},
+		"Selected": {
+			reason: "The selected key should replace the name, algorithm and secret of the configured key.",
+			key:    to("internal"),
+			mod:    keys,
+			creds:  tsig(),
+			want: want{creds: map[string]string{
+				keyRFC: keyBasedTransactionRFC, keyServer: "ns1.example.com", transcationKeyName: "internal-key.", transactionKeyAlgorithm: "hmac-sha512", transactionKeySecret: "aW50ZXJuYWw=",
+			}},
+		},
+		"SelectedWithoutAlgorithm": {
+			reason: "A selected key without an algorithm should use the algorithm of the credentials.",
+			key:    to("external"),
+			mod:    keys,
+			creds:  tsig(),
+			want: want{creds: map[string]string{
+				keyRFC: keyBasedTransactionRFC, keyServer: "ns1.example.com", transcationKeyName: "external-key.", transactionKeyAlgorithm: "hmac-sha256", transactionKeySecret: "ZXh0ZXJuYWw=",
+			}},
+		},
+		"UnknownKey": {
+			reason: "Selecting a key the ProviderConfig does not define should fail.",
+			key:    to("missing"),
+			mod:    keys,
+			creds:  tsig(),
+			want:   want{creds: tsig(), err: errors.Errorf(errUnknownTSIGKey, AnnotationTSIGKey, "missing")},
+		},
+		"NoKeys": {
+			reason: "Selecting a key of a ProviderConfig without tsigKeys should fail.",
+			key:    to("internal"),
+			creds:  tsig(),
+			want:   want{creds: tsig(), err: errors.Errorf(errUnknownTSIGKey, AnnotationTSIGKey, "internal")},
+		},
+		"NotTSIG": {
+			reason: "Selecting a key for GSS-TSIG credentials should fail.",
+			key:    to("internal"),
+			mod:    keys,
+			creds:  map[string]string{keyRFC: gsstsigRFC},
+			want:   want{creds: map[string]string{keyRFC: gsstsigRFC}, err: errors.Errorf(errTSIGKeyNeedsRFC, "internal", keyBasedTransactionRFC)},
+		},
+		"NotSecretSource": {
+			reason: "Selecting a key of credentials that are not read from a Secret should fail.",
+			key:    to("internal"),
+			mod: func(spec *namespacedv1beta1.ProviderConfigSpec) {
+				keys(spec)
+				spec.Credentials.Source = xpv1.CredentialsSourceEnvironment
+			},
+			creds: tsig(),
+			want:  want{creds: tsig(), err: errors.Errorf(errTSIGKeyNeedsSecret, "internal")},
+		},
+		"SecretNotSet": {
+			reason: "Selecting a key whose secret is not set in the credentials Secret should fail.",
+			key:    to("unset"),
+			mod:    keys,
+			creds:  tsig(),
+			want:   want{creds: tsig(), err: errors.Errorf(errTSIGKeySecretNotFound, "unset", "unset")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			secret := testSecret("{}")
+			secret.Data["internal"] = []byte("aW50ZXJuYWw=")
+			secret.Data["external"] = []byte("ZXh0ZXJuYWw=")
+			pc := testProviderConfigWith(tc.mod)
+			pc.Spec.Credentials.SecretRef.Namespace = testNamespace
+
+			mg := testRecordSet()
+			if tc.key != nil {
+				mg.SetAnnotations(map[string]string{AnnotationTSIGKey: *tc.key})
+			}
+
+			err := applyTSIGKey(context.Background(), testClient(t, secret), resource.CommonCredentialExtractor, &pc.Spec, mg, tc.creds)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\napplyTSIGKey(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, tc.creds); diff != "" {
+				t.Errorf("\n%s\napplyTSIGKey(...): -want credentials, +got credentials:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// usageDebouncer coalesces the ProviderConfigUsage writes of a managed
+// resource. A resource whose usage was tracked within the debounce period is
+// not tracked again unless it references a different ProviderConfig. Usages
+// are only created and never removed while a resource exists, so skipping a
+// repeated write does not lose any usage.
+type usageDebouncer struct {
+	period time.Duration
+
+	mu      sync.Mutex
+	tracked map[usageKey]time.Time
+}
+
+type usageKey struct {
+	uid    types.UID
+	kind   string
+	config string
+}
+
+func newUsageDebouncer(period time.Duration) *usageDebouncer {
+	return &usageDebouncer{period: period, tracked: map[usageKey]time.Time{}}
+}
+
+// Track tracks the usage of the given managed resource with the supplied
+// tracker, unless it was tracked within the debounce period. A nil
+// usageDebouncer always tracks.
+func (d *usageDebouncer) Track(ctx context.Context, mg resource.Managed, kind, config string, t resource.Tracker) error {
+	if d == nil || d.period <= 0 {
+		return t.Track(ctx, mg)
+	}
+
+	key := usageKey{uid: mg.GetUID(), kind: kind, config: config}
+	now := time.Now()
+
+	d.mu.Lock()
+	last, ok := d.tracked[key]
+	d.mu.Unlock()
+	if ok && now.Sub(last) < d.period {
+		return nil
+	}
+
+	if err := t.Track(ctx, mg); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, at := range d.tracked {
+		if now.Sub(at) >= d.period {
+			delete(d.tracked, k)
+		}
+	}
+	d.tracked[key] = now
+	return nil
+}
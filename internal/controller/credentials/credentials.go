@@ -0,0 +1,176 @@
+// Package credentials reports whether the credentials of ProviderConfigs are
+// valid through a condition on their status, and an event when they become
+// invalid.
+package credentials
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/upjet/v2/pkg/controller"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/dana-team/provider-dns-v2/internal/clients"
+)
+
+const (
+	errGetProviderConfig    = "cannot get ProviderConfig"
+	errUpdateStatus         = "cannot update ProviderConfig status"
+	errAddFileWatcher       = "cannot add the credentials file watcher"
+	controllerNamePrefix    = "credentials/"
+	defaultValidationPeriod = 10 * time.Minute
+)
+
+// TypeCredentialsValid indicates whether the credentials of a ProviderConfig
+// build a valid provider configuration.
+const TypeCredentialsValid xpv1.ConditionType = "CredentialsValid"
+
+// Reasons of the CredentialsValid condition.
+const (
+	ReasonValid   xpv1.ConditionReason = "Valid"
+	ReasonInvalid xpv1.ConditionReason = "Invalid"
+)
+
+// ReasonCredentialsInvalid is the reason of the Warning event recorded when
+// the credentials of a ProviderConfig become invalid.
+const ReasonCredentialsInvalid event.Reason = "CredentialsInvalid"
+
+// ReasonUnknownCredentialKeys is the reason of the Warning event recorded when
+// the credentials of a ProviderConfig contain keys the provider ignores.
+const ReasonUnknownCredentialKeys event.Reason = "UnknownCredentialKeys"
+
+const msgUnknownCredentialKeys = "Credentials contain unknown keys, which are ignored: "
+
+// A ProviderConfig whose credentials are validated.
+type ProviderConfig interface {
+	client.Object
+	resource.Conditioned
+}
+
+// Setup adds a controller that validates the credentials of the
+// ProviderConfigs of the given type. The credentials are validated again
+// every poll interval, since changes of the credentials Secret do not
+// trigger a reconcile, and as soon as a credentials file of the Filesystem
+// source changes.
+func Setup(mgr ctrl.Manager, o controller.Options, groupKind string, of func() ProviderConfig) error {
+	name := controllerNamePrefix + strings.ToLower(groupKind)
+
+	period := o.PollInterval
+	if period <= 0 {
+		period = defaultValidationPeriod
+	}
+
+	watcher := newFileWatcher(fileWatchInterval, of)
+	if err := mgr.Add(watcher); err != nil {
+		return errors.Wrap(err, errAddFileWatcher)
+	}
+
+	r := &reconciler{
+		client:  mgr.GetClient(),
+		log:     o.Logger.WithValues("controller", name),
+		record:  event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
+		of:      of,
+		period:  period,
+		watcher: watcher,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(of()).
+		WatchesRawSource(source.Channel(watcher.events, &handler.EnqueueRequestForObject{})).
+		Complete(r)
+}
+
+type reconciler struct {
+	client  client.Client
+	log     logging.Logger
+	record  event.Recorder
+	of      func() ProviderConfig
+	period  time.Duration
+	watcher *fileWatcher
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+
+	pc := r.of()
+	if err := r.client.Get(ctx, req.NamespacedName, pc); err != nil {
+		if kerrors.IsNotFound(err) {
+			r.watcher.forget(req.NamespacedName)
+		}
+		return reconcile.Result{}, errors.Wrap(client.IgnoreNotFound(err), errGetProviderConfig)
+	}
+	if pc.GetDeletionTimestamp() != nil {
+		r.watcher.forget(req.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+
+	cond := xpv1.Condition{
+		Type:               TypeCredentialsValid,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonValid,
+		ObservedGeneration: pc.GetGeneration(),
+	}
+	var unknown []string
+	pcSpec, err := clients.ProviderConfigSpec(pc)
+	if err == nil {
+		if fs := pcSpec.Credentials.Fs; pcSpec.Credentials.Source == xpv1.CredentialsSourceFilesystem && fs != nil {
+			r.watcher.watch(req.NamespacedName, fs.Path)
+		} else {
+			r.watcher.forget(req.NamespacedName)
+		}
+		unknown, err = clients.ValidateCredentials(ctx, r.client, pcSpec)
+	}
+	if err == nil && len(unknown) > 0 {
+		cond.Message = msgUnknownCredentialKeys + strings.Join(unknown, ", ")
+	}
+	if err != nil {
+		log.Debug("Invalid ProviderConfig credentials", "error", err)
+		cond.Status = corev1.ConditionFalse
+		cond.Reason = ReasonInvalid
+		cond.Message = err.Error()
+	}
+
+	result := reconcile.Result{RequeueAfter: r.period}
+	if clients.IsCredentialsNotFound(err) || clients.IsCredentialsRotating(err) {
+		// The Secret may be about to be created, or the credentials file
+		// about to be fully written, so check again with backoff rather than
+		// after a full period.
+		result = reconcile.Result{Requeue: true}
+	}
+
+	if current := pc.GetCondition(TypeCredentialsValid); current.Equal(cond) {
+		return result, nil
+	}
+	// Events are only recorded when the condition changes, so that validating
+	// the same invalid credentials every period does not repeat them.
+	if cond.Reason == ReasonInvalid {
+		r.record.Event(pc, event.Warning(ReasonCredentialsInvalid, err))
+	}
+	if len(unknown) > 0 {
+		r.record.Event(pc, event.Warning(ReasonUnknownCredentialKeys, errors.New(msgUnknownCredentialKeys+strings.Join(unknown, ", "))))
+	}
+	pc.SetConditions(cond)
+	if err := r.client.Status().Update(ctx, pc); err != nil {
+		if kerrors.IsConflict(err) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+	}
+	return result, nil
+}
@@ -0,0 +1,214 @@
+package credentials
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/event"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/dana-team/provider-dns-v2/apis/namespaced"
+	"github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+const (
+	validCredentials   = `{"rfc":"2845","server":"ns1.example.com","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"c2VjcmV0"}`
+	invalidCredentials = `{"rfc":"2845","server":"ns1.example.com","key_name":"tsig.","key_algorithm":"hmac-sha3","key_secret":"c2VjcmV0"}`
+	unknownCredentials = `{"rfc":"2845","server":"ns1.example.com","key_name":"tsig.","key_algorithm":"hmac-sha256","key_secret":"c2VjcmV0","timout":"5s"}`
+)
+
+var key = types.NamespacedName{Namespace: "default", Name: "default"}
+
+// recordedEvent is the type and reason of a recorded event.
+type recordedEvent struct {
+	Type   string
+	Reason string
+}
+
+// testReconciler returns a reconciler of a namespaced ProviderConfig reading
+// its credentials from a Secret holding the given credentials, or from a
+// missing Secret when they are empty.
+func testReconciler(t *testing.T, creds string) (*reconciler, client.Client, *record.FakeRecorder) {
+	t.Helper()
+	s := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, namespaced.AddToScheme} {
+		if err := add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pc := &v1beta1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Generation: 1},
+		Spec: v1beta1.ProviderConfigSpec{Credentials: v1beta1.ProviderCredentials{
+			Source:                    xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "dns-creds"}, Key: "credentials"}},
+		}},
+	}
+	objs := []client.Object{pc}
+	if creds != "" {
+		objs = append(objs, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: "dns-creds"},
+			Data:       map[string][]byte{"credentials": []byte(creds)},
+		})
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).WithStatusSubresource(pc).Build()
+
+	rec := record.NewFakeRecorder(10)
+	of := func() ProviderConfig { return &v1beta1.ProviderConfig{} }
+	return &reconciler{
+		client:  c,
+		log:     logging.NewNopLogger(),
+		record:  event.NewAPIRecorder(rec),
+		of:      of,
+		period:  time.Minute,
+		watcher: newFileWatcher(fileWatchInterval, of),
+	}, c, rec
+}
+
+// drain returns the type and reason of the events recorded so far.
+func drain(rec *record.FakeRecorder) []recordedEvent {
+	var events []recordedEvent
+	for {
+		select {
+		case e := <-rec.Events:
+			parts := strings.SplitN(e, " ", 3)
+			events = append(events, recordedEvent{Type: parts[0], Reason: parts[1]})
+		default:
+			return events
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	type want struct {
+		result reconcile.Result
+		status corev1.ConditionStatus
+		reason xpv1.ConditionReason
+		events []recordedEvent
+	}
+
+	cases := map[string]struct {
+		reason string
+		creds  string
+		want   want
+	}{
+		"Valid": {
+			reason: "Valid credentials should set the condition without recording an event.",
+			creds:  validCredentials,
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				status: corev1.ConditionTrue,
+				reason: ReasonValid,
+			},
+		},
+		"Invalid": {
+			reason: "Invalid credentials should record a CredentialsInvalid warning.",
+			creds:  invalidCredentials,
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				status: corev1.ConditionFalse,
+				reason: ReasonInvalid,
+				events: []recordedEvent{{Type: string(event.TypeWarning), Reason: string(ReasonCredentialsInvalid)}},
+			},
+		},
+		"UnknownKeys": {
+			reason: "Valid credentials with unknown keys should record an UnknownCredentialKeys warning.",
+			creds:  unknownCredentials,
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				status: corev1.ConditionTrue,
+				reason: ReasonValid,
+				events: []recordedEvent{{Type: string(event.TypeWarning), Reason: string(ReasonUnknownCredentialKeys)}},
+			},
+		},
+		"SecretNotFound": {
+			reason: "A missing Secret should record a CredentialsInvalid warning and be checked again with backoff.",
+			want: want{
+				result: reconcile.Result{Requeue: true},
+				status: corev1.ConditionFalse,
+				reason: ReasonInvalid,
+				events: []recordedEvent{{Type: string(event.TypeWarning), Reason: string(ReasonCredentialsInvalid)}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, c, rec := testReconciler(t, tc.creds)
+
+			got, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+			if err != nil {
+				t.Fatalf("\n%s\nReconcile(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, drain(rec)); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+
+			pc := &v1beta1.ProviderConfig{}
+			if err := c.Get(context.Background(), key, pc); err != nil {
+				t.Fatal(err)
+			}
+			cond := pc.GetCondition(TypeCredentialsValid)
+			if diff := cmp.Diff(tc.want.status, cond.Status); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want condition status, +got condition status:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, cond.Reason); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want condition reason, +got condition reason:\n%s", tc.reason, diff)
+			}
+
+			// Validating the same credentials again leaves the condition
+			// unchanged, which must not repeat the events.
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("\n%s\nReconcile(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff([]recordedEvent(nil), drain(rec)); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): an unchanged condition should record no event: -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcileConditionChanged(t *testing.T) {
+	r, c, rec := testReconciler(t, validCredentials)
+	ctx := context.Background()
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]recordedEvent(nil), drain(rec)); diff != "" {
+		t.Errorf("Reconcile(...): valid credentials should record no event: -want, +got:\n%s", diff)
+	}
+
+	s := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: key.Namespace, Name: "dns-creds"}, s); err != nil {
+		t.Fatal(err)
+	}
+	s.Data["credentials"] = []byte(invalidCredentials)
+	if err := c.Update(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key}); err != nil {
+		t.Fatal(err)
+	}
+	want := []recordedEvent{{Type: string(event.TypeWarning), Reason: string(ReasonCredentialsInvalid)}}
+	if diff := cmp.Diff(want, drain(rec)); diff != "" {
+		t.Errorf("Reconcile(...): credentials becoming invalid should record one event: -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,113 @@
+package credentials
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// fileWatchInterval is how often the credentials files of the ProviderConfigs
+// with the Filesystem source are checked for changes.
+const fileWatchInterval = 30 * time.Second
+
+// A fileWatcher enqueues ProviderConfigs whose credentials are read from the
+// filesystem when the content of their file changes. Files mounted from a
+// Secret or by a CSI driver rotate without any object changing, so they would
+// otherwise only be validated again after a full period.
+type fileWatcher struct {
+	interval time.Duration
+	of       func() ProviderConfig
+	events   chan event.GenericEvent
+
+	mu    sync.Mutex
+	files map[types.NamespacedName]*watchedFile
+}
+
+type watchedFile struct {
+	path   string
+	digest string
+}
+
+func newFileWatcher(interval time.Duration, of func() ProviderConfig) *fileWatcher {
+	return &fileWatcher{
+		interval: interval,
+		of:       of,
+		events:   make(chan event.GenericEvent),
+		files:    map[types.NamespacedName]*watchedFile{},
+	}
+}
+
+// watch watches the file at the given path for the given ProviderConfig,
+// replacing the file it was watched for before.
+func (w *fileWatcher) watch(key types.NamespacedName, path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if f, ok := w.files[key]; ok && f.path == path {
+		return
+	}
+	w.files[key] = &watchedFile{path: path, digest: fileDigest(path)}
+}
+
+// forget stops watching the file of the given ProviderConfig.
+func (w *fileWatcher) forget(key types.NamespacedName) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.files, key)
+}
+
+// Start checks the watched files every interval until the given context is
+// done.
+func (w *fileWatcher) Start(ctx context.Context) error {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+		for _, key := range w.changed() {
+			pc := w.of()
+			pc.SetName(key.Name)
+			pc.SetNamespace(key.Namespace)
+			select {
+			case w.events <- event.GenericEvent{Object: pc}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// changed returns the ProviderConfigs whose file changed since it was last
+// checked.
+func (w *fileWatcher) changed() []types.NamespacedName {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var keys []types.NamespacedName
+	for key, f := range w.files {
+		if digest := fileDigest(f.path); digest != f.digest {
+			f.digest = digest
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// fileDigest returns the digest of the content of the file at the given path,
+// or an empty string when it cannot be read, so that a file appearing or
+// disappearing counts as a change.
+func fileDigest(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,113 @@
+package credentials
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+)
+
+func TestFileWatcherChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	write := func(data string) func(t *testing.T) {
+		return func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	remove := func(t *testing.T) {
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := newFileWatcher(fileWatchInterval, func() ProviderConfig { return &v1beta1.ProviderConfig{} })
+	write(validCredentials)(t)
+	w.watch(key, path)
+
+	// The steps run in order against the same watcher.
+	steps := []struct {
+		reason string
+		change func(t *testing.T)
+		want   []types.NamespacedName
+	}{
+		{
+			reason: "An unchanged file should not be reported.",
+		},
+		{
+			reason: "A rotated file should be reported.",
+			change: write(invalidCredentials),
+			want:   []types.NamespacedName{key},
+		},
+		{
+			reason: "A rotated file should only be reported once.",
+		},
+		{
+			reason: "A file rewritten with the same content should not be reported.",
+			change: write(invalidCredentials),
+		},
+		{
+			reason: "A removed file should be reported.",
+			change: remove,
+			want:   []types.NamespacedName{key},
+		},
+		{
+			reason: "A file that appears again should be reported.",
+			change: write(validCredentials),
+			want:   []types.NamespacedName{key},
+		},
+	}
+
+	for i, s := range steps {
+		if s.change != nil {
+			s.change(t)
+		}
+		if diff := cmp.Diff(s.want, w.changed()); diff != "" {
+			t.Errorf("\nstep %d: %s\nchanged(): -want, +got:\n%s", i, s.reason, diff)
+		}
+	}
+
+	w.forget(key)
+	write(unknownCredentials)(t)
+	if got := w.changed(); len(got) != 0 {
+		t.Errorf("changed(): want a forgotten file not to be reported, got %v", got)
+	}
+}
+
+func TestFileWatcherStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(path, []byte(validCredentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w := newFileWatcher(10*time.Millisecond, func() ProviderConfig { return &v1beta1.ProviderConfig{} })
+	w.watch(key, path)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Start(ctx) }()
+
+	if err := os.WriteFile(path, []byte(invalidCredentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e := <-w.events:
+		if diff := cmp.Diff(key, types.NamespacedName{Namespace: e.Object.GetNamespace(), Name: e.Object.GetName()}); diff != "" {
+			t.Errorf("Start(...): want the ProviderConfig of the rotated file enqueued: -want, +got:\n%s", diff)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Start(...): want the ProviderConfig of the rotated file enqueued, got no event")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Start(...): %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/v2/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/v2/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/dana-team/provider-dns-v2/apis/namespaced"
+	"github.com/dana-team/provider-dns-v2/apis/namespaced/recordset/v1alpha1"
+)
+
+// TestObserveOnly checks that records with the Observe management policy are
+// only ever observed, with the management policy options the generated
+// controllers use.
+func TestObserveOnly(t *testing.T) {
+	now := metav1.Now()
+
+	type args struct {
+		policies xpv1.ManagementPolicies
+		exists   bool
+		deleting bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"ObserveDrifted": {
+			reason: "A drifted observe-only record should not be updated.",
+			args:   args{policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve}, exists: true},
+			want:   []string{"Observe"},
+		},
+		"ObserveMissing": {
+			reason: "A missing observe-only record should not be created.",
+			args:   args{policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve}},
+			want:   []string{"Observe"},
+		},
+		"ObserveDeleting": {
+			reason: "Deleting an observe-only record should not delete the DNS record.",
+			args:   args{policies: xpv1.ManagementPolicies{xpv1.ManagementActionObserve}, exists: true, deleting: true},
+			want:   nil,
+		},
+		"AllDrifted": {
+			reason: "A drifted record with the default management policy should be updated.",
+			args:   args{policies: xpv1.ManagementPolicies{xpv1.ManagementActionAll}, exists: true},
+			want:   []string{"Observe", "Update"},
+		},
+		"AllMissing": {
+			reason: "A missing record with the default management policy should be created.",
+			args:   args{policies: xpv1.ManagementPolicies{xpv1.ManagementActionAll}},
+			want:   []string{"Observe", "Create"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rs := &v1alpha1.ARecordSet{}
+			rs.SetName("www")
+			rs.SetNamespace("default")
+			rs.SetManagementPolicies(tc.args.policies)
+			meta.SetExternalName(rs, "www.example.com.")
+			meta.AddFinalizer(rs, managed.FinalizerName)
+			if tc.args.deleting {
+				rs.SetDeletionTimestamp(&now)
+			}
+
+			s := runtime.NewScheme()
+			for _, add := range []func(*runtime.Scheme) error{clientgoscheme.AddToScheme, namespaced.AddToScheme} {
+				if err := add(s); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c := clientfake.NewClientBuilder().WithScheme(s).WithObjects(rs).WithStatusSubresource(rs).Build()
+
+			var calls []string
+			ext := managed.ExternalClientFns{
+				ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+					calls = append(calls, "Observe")
+					return managed.ExternalObservation{ResourceExists: tc.args.exists}, nil
+				},
+				CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+					calls = append(calls, "Create")
+					return managed.ExternalCreation{}, nil
+				},
+				UpdateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+					calls = append(calls, "Update")
+					return managed.ExternalUpdate{}, nil
+				},
+				DeleteFn: func(_ context.Context, _ resource.Managed) (managed.ExternalDelete, error) {
+					calls = append(calls, "Delete")
+					return managed.ExternalDelete{}, nil
+				},
+				DisconnectFn: func(_ context.Context) error { return nil },
+			}
+			connector := managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return ext, nil
+			})
+
+			r := managed.NewReconciler(&fake.Manager{Client: c, Scheme: s}, resource.ManagedKind(v1alpha1.ARecordSet_GroupVersionKind),
+				managed.WithExternalConnector(connector),
+				managed.WithInitializers(managed.NewNameAsExternalName(c)),
+				managed.WithManagementPolicies(),
+			)
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "www"}}); err != nil {
+				t.Fatalf("\n%s\nReconcile(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, calls); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want external calls, +got external calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -8,10 +8,11 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/dana-team/provider-dns-v2/apis/namespaced/v1beta1"
+	"github.com/dana-team/provider-dns-v2/internal/controller/credentials"
 )
 
 // Setup adds a controller that reconciles ProviderConfigs and ClusterProviderConfigs
-// by accounting for their current usage.
+// by accounting for their current usage, and ones that validate their credentials.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	if err := setupCluster(mgr, o); err != nil {
 		return err
@@ -30,14 +31,18 @@ func setupCluster(mgr ctrl.Manager, o controller.Options) error {
 		UsageList: v1beta1.ProviderConfigUsageListGroupVersionKind,
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1beta1.ClusterProviderConfig{}).
 		Watches(&v1beta1.ProviderConfigUsage{}, &resource.EnqueueRequestForProviderConfig{}).
 		Complete(providerconfig.NewReconciler(mgr, of,
 			providerconfig.WithLogger(o.Logger.WithValues("controller", name)),
-			providerconfig.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			providerconfig.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))); err != nil {
+		return err
+	}
+
+	return credentials.Setup(mgr, o, v1beta1.ClusterProviderConfigGroupKind, func() credentials.ProviderConfig { return &v1beta1.ClusterProviderConfig{} })
 }
 
 // setupNamespaced adds a controller that reconciles ProviderConfigs by accounting for
@@ -51,14 +56,18 @@ func setupNamespaced(mgr ctrl.Manager, o controller.Options) error {
 		UsageList: v1beta1.ProviderConfigUsageListGroupVersionKind,
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
 		For(&v1beta1.ProviderConfig{}).
 		Watches(&v1beta1.ProviderConfigUsage{}, &resource.EnqueueRequestForProviderConfig{}).
 		Complete(providerconfig.NewReconciler(mgr, of,
 			providerconfig.WithLogger(o.Logger.WithValues("controller", name)),
-			providerconfig.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			providerconfig.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))); err != nil {
+		return err
+	}
+
+	return credentials.Setup(mgr, o, v1beta1.ProviderConfigGroupKind, func() credentials.ProviderConfig { return &v1beta1.ProviderConfig{} })
 }
 
 // SetupGated adds a controller that reconciles ProviderConfigs and ClusterProviderConfigs
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"github.com/crossplane/upjet/v2/pkg/controller"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	controllerCluster "github.com/dana-team/provider-dns-v2/internal/controller/cluster"
+	controllerNamespaced "github.com/dana-team/provider-dns-v2/internal/controller/namespaced"
+	"github.com/dana-team/provider-dns-v2/internal/webhook"
+)
+
+const (
+	errSetupCluster    = "cannot setup cluster-scoped Dns-v2 controllers"
+	errSetupNamespaced = "cannot setup namespaced Dns-v2 controllers"
+	errSetupWebhooks   = "cannot setup Dns-v2 validating webhooks"
+	errUnknownScope    = "unknown controller scope %q"
+)
+
+// Scope selects which controllers are set up by SetupAll.
+type Scope string
+
+const (
+	// ScopeAll sets up both the cluster-scoped and the namespaced controllers.
+	ScopeAll Scope = "all"
+	// ScopeCluster sets up only the cluster-scoped (legacy) controllers.
+	ScopeCluster Scope = "cluster"
+	// ScopeNamespaced sets up only the namespaced (modern) controllers.
+	ScopeNamespaced Scope = "namespaced"
+)
+
+// Options configures the controllers set up by SetupAll.
+type Options struct {
+	// Cluster are the options of the cluster-scoped controllers.
+	Cluster controller.Options
+	// Namespaced are the options of the namespaced controllers.
+	Namespaced controller.Options
+	// Gated sets the controllers up gated on their CRDs becoming available.
+	Gated bool
+}
+
+// SetupAll creates the controllers of the given scope and adds them to the
+// supplied manager, along with the validating webhooks of their records when
+// webhooks are started.
+func SetupAll(mgr ctrl.Manager, o Options, scope Scope) error {
+	clusterSetup, namespacedSetup := controllerCluster.Setup, controllerNamespaced.Setup
+	if o.Gated {
+		clusterSetup, namespacedSetup = controllerCluster.SetupGated, controllerNamespaced.SetupGated
+	}
+
+	switch scope {
+	case ScopeAll, ScopeCluster, ScopeNamespaced:
+	default:
+		return errors.Errorf(errUnknownScope, scope)
+	}
+	if scope != ScopeNamespaced {
+		if err := clusterSetup(mgr, o.Cluster); err != nil {
+			return errors.Wrap(err, errSetupCluster)
+		}
+		if o.Cluster.StartWebhooks {
+			if err := webhook.SetupCluster(mgr); err != nil {
+				return errors.Wrap(err, errSetupWebhooks)
+			}
+		}
+	}
+	if scope != ScopeCluster {
+		if err := namespacedSetup(mgr, o.Namespaced); err != nil {
+			return errors.Wrap(err, errSetupNamespaced)
+		}
+		if o.Namespaced.StartWebhooks {
+			if err := webhook.SetupNamespaced(mgr); err != nil {
+				return errors.Wrap(err, errSetupWebhooks)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Package webhook validates records and record sets on admission, so that
+// malformed names and TTLs are rejected with the field at fault instead of
+// failing later inside Terraform.
+package webhook
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/crossplane/upjet/v2/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clusterrecord "github.com/dana-team/provider-dns-v2/apis/cluster/record/v1alpha1"
+	clusterrecordset "github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1"
+	namespacedrecord "github.com/dana-team/provider-dns-v2/apis/namespaced/record/v1alpha1"
+	namespacedrecordset "github.com/dana-team/provider-dns-v2/apis/namespaced/recordset/v1alpha1"
+	"github.com/dana-team/provider-dns-v2/config"
+)
+
+const (
+	errNotTerraformed = "object is not a Terraform managed resource"
+	errGetParameters  = "cannot get the parameters of the object"
+
+	keyName = "name"
+	keyZone = "zone"
+	keyTTL  = "ttl"
+)
+
+// SetupCluster registers the validating webhooks of the cluster-scoped
+// records and record sets.
+func SetupCluster(mgr ctrl.Manager) error {
+	return setup(mgr, map[runtime.Object]*validator{
+		&clusterrecord.CNAMERecord{}:      {},
+		&clusterrecord.PTRRecord{}:        {allowIP: true},
+		&clusterrecordset.AAAARecordSet{}: {},
+		&clusterrecordset.ARecordSet{}:    {},
+		&clusterrecordset.MXRecordSet{}:   {},
+		&clusterrecordset.NSRecordSet{}:   {},
+		&clusterrecordset.SRVRecordSet{}:  {},
+		&clusterrecordset.TXTRecordSet{}:  {},
+	})
+}
+
+// SetupNamespaced registers the validating webhooks of the namespaced records
+// and record sets.
+func SetupNamespaced(mgr ctrl.Manager) error {
+	return setup(mgr, map[runtime.Object]*validator{
+		&namespacedrecord.CNAMERecord{}:      {},
+		&namespacedrecord.PTRRecord{}:        {allowIP: true},
+		&namespacedrecordset.AAAARecordSet{}: {},
+		&namespacedrecordset.ARecordSet{}:    {},
+		&namespacedrecordset.MXRecordSet{}:   {},
+		&namespacedrecordset.NSRecordSet{}:   {},
+		&namespacedrecordset.SRVRecordSet{}:  {},
+		&namespacedrecordset.TXTRecordSet{}:  {},
+	})
+}
+
+func setup(mgr ctrl.Manager, validators map[runtime.Object]*validator) error {
+	for obj, v := range validators {
+		if err := ctrl.NewWebhookManagedBy(mgr).For(obj).WithValidator(v).Complete(); err != nil {
+			return errors.Wrapf(err, "cannot register the validating webhook of %T", obj)
+		}
+	}
+	return nil
+}
+
+// validator validates the name, zone and TTL of a record or record set.
+type validator struct {
+	// allowIP allows the name to be an IP address, which PTR records derive
+	// their reverse name from.
+	allowIP bool
+}
+
+var _ admission.CustomValidator = &validator{}
+
+// ValidateCreate validates a created record.
+func (v *validator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(obj)
+}
+
+// ValidateUpdate validates an updated record. Updates of records being
+// deleted, and updates leaving their parameters unchanged, such as finalizer
+// removals and external name annotation writes, are always allowed, so that
+// records created before the webhook can still be reconciled and deleted.
+func (v *validator) ValidateUpdate(_ context.Context, oldObj, obj runtime.Object) (admission.Warnings, error) {
+	tr, ok := obj.(resource.Terraformed)
+	if !ok {
+		return nil, errors.New(errNotTerraformed)
+	}
+	if tr.GetDeletionTimestamp() != nil {
+		return nil, nil
+	}
+	unchanged, err := parametersUnchanged(oldObj, tr)
+	if err != nil || unchanged {
+		return nil, err
+	}
+	return nil, v.validate(obj)
+}
+
+// ValidateDelete allows every record to be deleted.
+func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// parametersUnchanged reports whether the forProvider and initProvider
+// parameters of the given objects are equal.
+func parametersUnchanged(oldObj runtime.Object, tr resource.Terraformed) (bool, error) {
+	old, ok := oldObj.(resource.Terraformed)
+	if !ok {
+		return false, errors.New(errNotTerraformed)
+	}
+	for _, get := range []func(resource.Terraformed) (map[string]any, error){
+		resource.Terraformed.GetParameters,
+		resource.Terraformed.GetInitParameters,
+	} {
+		before, err := get(old)
+		if err != nil {
+			return false, errors.Wrap(err, errGetParameters)
+		}
+		after, err := get(tr)
+		if err != nil {
+			return false, errors.Wrap(err, errGetParameters)
+		}
+		if !equality.Semantic.DeepEqual(before, after) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (v *validator) validate(obj runtime.Object) error {
+	tr, ok := obj.(resource.Terraformed)
+	if !ok {
+		return errors.New(errNotTerraformed)
+	}
+
+	forProvider, err := tr.GetParameters()
+	if err != nil {
+		return errors.Wrap(err, errGetParameters)
+	}
+	initProvider, err := tr.GetInitParameters()
+	if err != nil {
+		return errors.Wrap(err, errGetParameters)
+	}
+
+	spec := field.NewPath("spec")
+	errs := v.validateParameters(spec.Child("forProvider"), forProvider)
+	errs = append(errs, v.validateParameters(spec.Child("initProvider"), initProvider)...)
+	if len(errs) == 0 {
+		return nil
+	}
+	return kerrors.NewInvalid(obj.GetObjectKind().GroupVersionKind().GroupKind(), tr.GetName(), errs)
+}
+
+func (v *validator) validateParameters(path *field.Path, params map[string]any) field.ErrorList {
+	var errs field.ErrorList
+
+	zone, _ := params[keyZone].(string)
+	if zone != "" {
+		if err := config.ValidateDomainName(zone, false); err != nil {
+			errs = append(errs, field.Invalid(path.Child(keyZone), zone, err.Error()))
+		}
+	}
+
+	// Names are validated like the provider translates them: "@", empty and
+	// zone-equal names refer to the apex, and fully qualified names are not
+	// qualified with the zone again.
+	if name, ok := params[keyName].(string); ok && !config.IsZoneApex(name, zone) && !(v.allowIP && net.ParseIP(name) != nil) {
+		fqdn := name
+		if zone != "" && !strings.HasSuffix(name, ".") {
+			fqdn = name + "." + zone
+		}
+		if err := config.ValidateDomainName(fqdn, true); err != nil {
+			errs = append(errs, field.Invalid(path.Child(keyName), name, err.Error()))
+		}
+	}
+
+	// TTLs are numbers in the Terraform parameters.
+	if ttl, ok := params[keyTTL].(float64); ok {
+		if err := config.ValidateTTL(ttl); err != nil {
+			errs = append(errs, field.Invalid(path.Child(keyTTL), int64(ttl), err.Error()))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/v2/pkg/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/dana-team/provider-dns-v2/apis/cluster/record/v1alpha1"
+	recordsetv1alpha1 "github.com/dana-team/provider-dns-v2/apis/cluster/recordset/v1alpha1"
+	"github.com/dana-team/provider-dns-v2/config"
+)
+
+const zone = "example.com."
+
+func to[T any](v T) *T { return &v }
+
+func aRecordSet(name string, ttl int64) *recordsetv1alpha1.ARecordSet {
+	rs := &recordsetv1alpha1.ARecordSet{}
+	rs.SetName("www")
+	rs.Spec.ForProvider.Zone = to(zone)
+	rs.Spec.ForProvider.Name = to(name)
+	rs.Spec.ForProvider.TTL = to(ttl)
+	return rs
+}
+
+func TestValidateCreate(t *testing.T) {
+	label63 := strings.Repeat("a", config.MaxLabelLength)
+	// long is a relative name that, qualified with the zone, is 254
+	// characters long.
+	long := strings.Join([]string{label63, label63, label63, strings.Repeat("a", 50)}, ".")
+	// longFQDN is a fully qualified name of 253 characters, which must not be
+	// qualified with the zone again.
+	longFQDN := strings.Join([]string{label63, label63, label63, strings.Repeat("a", 61)}, ".") + "."
+
+	cases := map[string]struct {
+		reason string
+		name   string
+		ttl    int64
+		want   bool
+	}{
+		"Valid": {
+			reason: "A relative name of valid labels should be allowed.",
+			name:   "www",
+			ttl:    300,
+		},
+		"Underscore": {
+			reason: "Underscores, as used by SRV and TXT records, should be allowed.",
+			name:   "_sip._tcp",
+		},
+		"Wildcard": {
+			reason: "A wildcard as the leftmost label should be allowed.",
+			name:   "*.dev",
+		},
+		"WildcardNotLeftmost": {
+			reason: "A wildcard that is not the leftmost label should be rejected.",
+			name:   "dev.*",
+			want:   true,
+		},
+		"InvalidCharacter": {
+			reason: "A label with characters other than letters, digits, hyphens and underscores should be rejected.",
+			name:   "w w",
+			want:   true,
+		},
+		"Hyphen": {
+			reason: "A label starting with a hyphen should be rejected.",
+			name:   "-www",
+			want:   true,
+		},
+		"LabelTooLong": {
+			reason: "A label longer than 63 characters should be rejected.",
+			name:   label63 + "a",
+			want:   true,
+		},
+		"NameTooLong": {
+			reason: "A name longer than 253 characters once qualified with the zone should be rejected.",
+			name:   long,
+			want:   true,
+		},
+		"FQDNAtLimit": {
+			reason: "A fully qualified name of 253 characters should not be qualified with the zone again.",
+			name:   longFQDN,
+		},
+		"ApexAt": {
+			reason: "The @ name should be treated as the zone apex.",
+			name:   "@",
+		},
+		"ApexEmpty": {
+			reason: "An empty name should be treated as the zone apex.",
+			name:   "",
+		},
+		"ApexZoneEqual": {
+			reason: "A name equal to the zone should be treated as the zone apex.",
+			name:   "Example.com",
+		},
+		"TTLNegative": {
+			reason: "A negative TTL should be rejected.",
+			name:   "www",
+			ttl:    -1,
+			want:   true,
+		},
+		"TTLTooLarge": {
+			reason: "A TTL larger than 2^31-1 should be rejected.",
+			name:   "www",
+			ttl:    config.MaxTTL + 1,
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := (&validator{}).ValidateCreate(context.Background(), aRecordSet(tc.name, tc.ttl))
+			if gotErr := err != nil; gotErr != tc.want {
+				t.Errorf("\n%s\nValidateCreate(...): want error %t, got %v", tc.reason, tc.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateCreatePTR(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		v      *validator
+		want   bool
+	}{
+		"AllowIP": {
+			reason: "A PTR record should be allowed to be named after an IP address.",
+			v:      &validator{allowIP: true},
+		},
+		"DisallowIP": {
+			reason: "Other records should not be allowed to be named after an IPv6 address.",
+			v:      &validator{},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &v1alpha1.PTRRecord{}
+			r.Spec.ForProvider.Zone = to("8.b.d.0.1.0.0.2.ip6.arpa.")
+			r.Spec.ForProvider.Name = to("2001:db8::1")
+			_, err := tc.v.ValidateCreate(context.Background(), r)
+			if gotErr := err != nil; gotErr != tc.want {
+				t.Errorf("\n%s\nValidateCreate(...): want error %t, got %v", tc.reason, tc.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	now := metav1.Now()
+
+	cases := map[string]struct {
+		reason string
+		old    *recordsetv1alpha1.ARecordSet
+		new    func(*recordsetv1alpha1.ARecordSet)
+		want   bool
+	}{
+		"Valid": {
+			reason: "An update to a valid record should be allowed.",
+			old:    aRecordSet("www", 300),
+			new:    func(rs *recordsetv1alpha1.ARecordSet) { rs.Spec.ForProvider.TTL = to[int64](600) },
+		},
+		"Invalid": {
+			reason: "An update to an invalid record should be rejected.",
+			old:    aRecordSet("www", 300),
+			new:    func(rs *recordsetv1alpha1.ARecordSet) { rs.Spec.ForProvider.Name = to("w w") },
+			want:   true,
+		},
+		"InvalidInitProvider": {
+			reason: "An update to an invalid initProvider should be rejected.",
+			old:    aRecordSet("www", 300),
+			new:    func(rs *recordsetv1alpha1.ARecordSet) { rs.Spec.InitProvider.Name = to("w w") },
+			want:   true,
+		},
+		"MetadataOnly": {
+			reason: "A metadata update of a record created before the webhook should be allowed even if it is invalid.",
+			old:    aRecordSet("w w", 300),
+			new: func(rs *recordsetv1alpha1.ARecordSet) {
+				meta.SetExternalName(rs, "w w.example.com.")
+				meta.AddFinalizer(rs, "finalizer.managedresource.crossplane.io")
+			},
+		},
+		"Deleting": {
+			reason: "An update of a record being deleted should be allowed even if it is invalid.",
+			old:    aRecordSet("w w", 300),
+			new: func(rs *recordsetv1alpha1.ARecordSet) {
+				rs.SetDeletionTimestamp(&now)
+				rs.Spec.ForProvider.TTL = to[int64](-1)
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			obj := tc.old.DeepCopy()
+			tc.new(obj)
+			_, err := (&validator{}).ValidateUpdate(context.Background(), tc.old, obj)
+			if gotErr := err != nil; gotErr != tc.want {
+				t.Errorf("\n%s\nValidateUpdate(...): want error %t, got %v", tc.reason, tc.want, err)
+			}
+		})
+	}
+}